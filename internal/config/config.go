@@ -34,10 +34,175 @@ type Config struct {
 	// Requires GOOGLE_APPLICATION_CREDENTIALS or gcloud ADC to be configured.
 	UseAdvancedAPI bool `json:"use_advanced_api"`
 
+	// Backend selects the speech-to-text engine: BackendGoogleFree,
+	// BackendGoogleCloud, BackendWhisperLocal, or BackendVoskLocal. Empty is
+	// treated as BackendGoogleFree, with UseAdvancedAPI kept as a legacy
+	// override for BackendGoogleCloud.
+	Backend string `json:"backend,omitempty"`
+
+	// Streaming enables incremental recognition: partial transcripts are
+	// typed as they arrive instead of waiting for end-of-phrase. Only takes
+	// effect for BackendGoogleCloud, which is the only backend whose API
+	// reports interim results; it's ignored for BackendGoogleFree and
+	// BackendWhisperLocal, which keep the batch behavior.
+	Streaming bool `json:"streaming"`
+
+	// WhisperModelPath is the path to a ggml-*.bin model file for
+	// BackendWhisperLocal. Leave blank to let the model manager resolve and
+	// download WhisperModelSize to the on-disk model cache.
+	WhisperModelPath string `json:"whisper_model_path,omitempty"`
+
+	// WhisperModelSize selects which model the manager downloads when
+	// WhisperModelPath is blank, e.g. "tiny.en", "base.en", "small.en", "medium.en".
+	WhisperModelSize string `json:"whisper_model_size,omitempty"`
+
+	// WhisperThreads is the number of CPU threads whisper.cpp uses for
+	// inference. 0 lets the binding pick a default.
+	WhisperThreads int `json:"whisper_threads,omitempty"`
+
+	// VoskModelPath is the path to an unpacked Vosk model directory for
+	// BackendVoskLocal. Leave blank to let the model manager resolve and
+	// download VoskModelName to the on-disk model cache.
+	VoskModelPath string `json:"vosk_model_path,omitempty"`
+
+	// VoskModelName selects which model the manager downloads when
+	// VoskModelPath is blank, e.g. "vosk-model-small-en-us-0.15".
+	VoskModelName string `json:"vosk_model_name,omitempty"`
+
+	// Vocabulary lists custom words/phrases (names, jargon, command words)
+	// that recognition should be biased toward. BackendGoogleCloud passes
+	// these to the API natively as SpeechContexts; every other backend gets
+	// the same effect via fuzzy post-processing that replaces near-homophones
+	// in the returned transcript (see speech.applyVocabulary).
+	Vocabulary []VocabPhrase `json:"vocabulary,omitempty"`
+
 	// EnablePunctuation adds punctuation to transcripts (typer level).
 	EnablePunctuation bool `json:"enable_punctuation"`
+
+	// EnableAutomaticPunctuation asks BackendGoogleCloud to add punctuation
+	// in the recognized transcript itself, upstream of EnablePunctuation's
+	// typer-level pass. Ignored by every other backend.
+	EnableAutomaticPunctuation bool `json:"enable_automatic_punctuation"`
+
+	// AlternativeLanguageCodes lists extra BCP-47 codes BackendGoogleCloud
+	// may detect alongside Language, picking whichever fits each utterance
+	// best — useful for bilingual speakers. Ignored by every other backend.
+	AlternativeLanguageCodes []string `json:"alternative_language_codes,omitempty"`
+
+	// Model selects BackendGoogleCloud's recognition model, e.g. "default",
+	// "command_and_search", "latest_long", "phone_call". Leave blank to let
+	// the API choose. Ignored by every other backend.
+	Model string `json:"model,omitempty"`
+
+	// UseEnhanced requests BackendGoogleCloud's enhanced models, where
+	// available for Model. Ignored by every other backend.
+	UseEnhanced bool `json:"use_enhanced"`
+
+	// ProfanityFilter asks BackendGoogleCloud to mask profanity in the
+	// transcript. Ignored by every other backend.
+	ProfanityFilter bool `json:"profanity_filter"`
+
+	// MaxAlternatives is how many candidate transcripts BackendGoogleCloud
+	// returns per utterance, used by speech.Recognizer.RecognizeN. 0 or 1
+	// means just the top transcript. Ignored by every other backend.
+	MaxAlternatives int32 `json:"max_alternatives,omitempty"`
+
+	// EnableWordTimeOffsets and EnableWordConfidence ask BackendGoogleCloud
+	// for per-word start/end times and confidence, used by
+	// speech.Recognizer.RecognizeWords. Ignored by every other backend.
+	EnableWordTimeOffsets bool `json:"enable_word_time_offsets"`
+	EnableWordConfidence  bool `json:"enable_word_confidence"`
+
+	// LongForm has BackendGoogleCloud rotate to a fresh streaming connection
+	// before Cloud Speech's ~5 minute per-stream limit cuts it off mid-phrase,
+	// instead of erroring out. See speech.Recognizer.recognizeCloudLongForm.
+	// Ignored by every other backend.
+	LongForm bool `json:"long_form"`
+
+	// GCSBucket is the Cloud Storage bucket speech.Recognizer.RecognizeFile
+	// stages audio in before handing it to LongRunningRecognize for bulk,
+	// file-based transcription. Required for RecognizeFile; unused otherwise.
+	GCSBucket string `json:"gcs_bucket,omitempty"`
+
+	// EventsSocket, if set, is the path of a Unix socket that the daemon
+	// listens on and broadcasts newline-delimited JSON lifecycle events to
+	// (see internal/events). Leave blank to disable the event stream.
+	EventsSocket string `json:"events_socket,omitempty"`
+
+	// SoundEnabled plays a short audio cue on recording start/stop/error/undo.
+	SoundEnabled bool `json:"sound_enabled"`
+
+	// NotifyEnabled fires a desktop notification (notify-send) for the same
+	// events as SoundEnabled.
+	NotifyEnabled bool `json:"notify_enabled"`
+
+	// PopupNotify additionally bridges the popup's Done/Error states to a
+	// native desktop notification via D-Bus (org.freedesktop.Notifications),
+	// so the result is still visible when the popup itself isn't — headless,
+	// locked-screen, or on a different workspace. Independent of
+	// NotifyEnabled, which covers every lifecycle event via notify-send.
+	PopupNotify bool `json:"popup_notify"`
+
+	// SoundStart, SoundStop, SoundError, and SoundUndo override the default
+	// cue played for each event. Leave blank for no cue for that event.
+	SoundStart string `json:"sound_start,omitempty"`
+	SoundStop  string `json:"sound_stop,omitempty"`
+	SoundError string `json:"sound_error,omitempty"`
+	SoundUndo  string `json:"sound_undo,omitempty"`
+
+	// Profiles holds named presets of the recognition-related fields (see
+	// applyProfileFields in profile.go) — e.g. a "Persian dictation" profile and
+	// an "English meeting notes" profile with different language,
+	// sensitivity, silence threshold, and API key. Hotkeys and sound cues
+	// are global and are not part of a profile. Use SaveProfile/ApplyProfile
+	// rather than writing this map directly.
+	Profiles map[string]Config `json:"profiles,omitempty"`
+
+	// ActiveProfile is the name of the Profiles entry last applied with
+	// ApplyProfile. Blank means no profile store is in use.
+	ActiveProfile string `json:"active_profile,omitempty"`
+
+	// CycleProfileHotkey rotates ActiveProfile to the next entry in
+	// Profiles (alphabetical by name) without opening the settings window.
+	CycleProfileHotkey string `json:"cycle_profile_hotkey,omitempty"`
+
+	// MacroRecordHotkey toggles XRecord-based macro capture: the first tap
+	// starts recording keyboard/mouse activity, the second tap stops it and
+	// saves the sequence under an auto-generated "macro-N" name (see
+	// typing.NextMacroName). Rename the saved file to control what a
+	// "run macro <name>" or "macro:<name>" voice trigger replays.
+	MacroRecordHotkey string `json:"macro_record_hotkey,omitempty"`
+
+	// TypingBackend selects the typing.Typer implementation: TypingBackendAuto,
+	// TypingBackendX11, TypingBackendWayland, or TypingBackendWaylandNative.
+	// Empty is treated as TypingBackendAuto.
+	TypingBackend string `json:"typing_backend,omitempty"`
 }
 
+// VocabPhrase is one custom-vocabulary entry — see Config.Vocabulary.
+// Boost is the Cloud Speech v1 SpeechContext boost value (0–20); other
+// backends ignore it and use an exact/fuzzy match on Phrase instead.
+type VocabPhrase struct {
+	Phrase string  `json:"phrase"`
+	Boost  float64 `json:"boost"`
+}
+
+// Recognition backend identifiers for Config.Backend.
+const (
+	BackendGoogleFree   = "google-free"
+	BackendGoogleCloud  = "google-cloud"
+	BackendWhisperLocal = "whisper-local"
+	BackendVoskLocal    = "vosk-local"
+)
+
+// Typer backend identifiers for Config.TypingBackend.
+const (
+	TypingBackendAuto          = "auto"
+	TypingBackendX11           = "x11"
+	TypingBackendWayland       = "wayland"
+	TypingBackendWaylandNative = "wayland-native"
+)
+
 func Default() *Config {
 	return &Config{
 		Hotkey:            "Alt-d",
@@ -45,6 +210,8 @@ func Default() *Config {
 		Timeout:           60,
 		SilenceChunks:     12,
 		Sensitivity:       2.5,
+		Backend:           BackendGoogleFree,
+		WhisperModelSize:  "base.en",
 		EnablePunctuation: true,
 	}
 }
@@ -80,6 +247,21 @@ func Load() (*Config, error) {
 	if cfg.Timeout < 5 {
 		cfg.Timeout = 60
 	}
+	switch cfg.Backend {
+	case BackendGoogleFree, BackendGoogleCloud, BackendWhisperLocal, BackendVoskLocal:
+	case "":
+		cfg.Backend = BackendGoogleFree
+		if cfg.UseAdvancedAPI {
+			cfg.Backend = BackendGoogleCloud
+		}
+	default:
+		cfg.Backend = BackendGoogleFree
+	}
+	switch cfg.TypingBackend {
+	case "", TypingBackendAuto, TypingBackendX11, TypingBackendWayland, TypingBackendWaylandNative:
+	default:
+		cfg.TypingBackend = TypingBackendAuto
+	}
 	return cfg, nil
 }
 
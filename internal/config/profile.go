@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+)
+
+// applyProfileFields copies the per-profile settings from p onto c. Hotkeys,
+// sound cues, and the profile store itself are global — they stay whatever
+// the top-level Config already has.
+func (c *Config) applyProfileFields(p Config) {
+	c.Language = p.Language
+	c.Timeout = p.Timeout
+	c.SilenceChunks = p.SilenceChunks
+	c.Sensitivity = p.Sensitivity
+	c.APIKey = p.APIKey
+	c.UseAdvancedAPI = p.UseAdvancedAPI
+	c.Backend = p.Backend
+	c.Streaming = p.Streaming
+	c.WhisperModelPath = p.WhisperModelPath
+	c.WhisperModelSize = p.WhisperModelSize
+	c.WhisperThreads = p.WhisperThreads
+	c.VoskModelPath = p.VoskModelPath
+	c.VoskModelName = p.VoskModelName
+	c.Vocabulary = p.Vocabulary
+	c.EnablePunctuation = p.EnablePunctuation
+	c.EnableAutomaticPunctuation = p.EnableAutomaticPunctuation
+	c.AlternativeLanguageCodes = p.AlternativeLanguageCodes
+	c.Model = p.Model
+	c.UseEnhanced = p.UseEnhanced
+	c.ProfanityFilter = p.ProfanityFilter
+	c.MaxAlternatives = p.MaxAlternatives
+	c.EnableWordTimeOffsets = p.EnableWordTimeOffsets
+	c.EnableWordConfidence = p.EnableWordConfidence
+	c.LongForm = p.LongForm
+	c.GCSBucket = p.GCSBucket
+}
+
+// profileSnapshot captures c's current profile-relevant fields, ready to
+// store under a name in Profiles or export to TOML.
+func (c *Config) profileSnapshot() Config {
+	var p Config
+	p.applyProfileFields(*c)
+	return p
+}
+
+// SaveProfile stores c's current profile-relevant fields under name,
+// creating or overwriting the entry.
+func (c *Config) SaveProfile(name string) {
+	if c.Profiles == nil {
+		c.Profiles = map[string]Config{}
+	}
+	c.Profiles[name] = c.profileSnapshot()
+}
+
+// ApplyProfile copies a stored preset's fields onto c and marks it active.
+func (c *Config) ApplyProfile(name string) error {
+	p, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("config: no such profile %q", name)
+	}
+	c.applyProfileFields(p)
+	c.ActiveProfile = name
+	return nil
+}
+
+// DuplicateProfile copies src's stored fields to a new entry dst.
+func (c *Config) DuplicateProfile(src, dst string) error {
+	p, ok := c.Profiles[src]
+	if !ok {
+		return fmt.Errorf("config: no such profile %q", src)
+	}
+	if c.Profiles == nil {
+		c.Profiles = map[string]Config{}
+	}
+	c.Profiles[dst] = p
+	return nil
+}
+
+// DeleteProfile removes name from the store, clearing ActiveProfile if it
+// was the one deleted.
+func (c *Config) DeleteProfile(name string) {
+	delete(c.Profiles, name)
+	if c.ActiveProfile == name {
+		c.ActiveProfile = ""
+	}
+}
+
+// ProfileNames returns the store's profile names, sorted alphabetically so
+// dropdowns and CycleProfile have a stable order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CycleProfile applies whichever profile is alphabetically after
+// ActiveProfile, wrapping around to the first, and returns its name. With
+// fewer than two profiles it is a no-op and returns "".
+func (c *Config) CycleProfile() (string, error) {
+	names := c.ProfileNames()
+	if len(names) < 2 {
+		return "", nil
+	}
+	next := names[0]
+	for i, n := range names {
+		if n == c.ActiveProfile {
+			next = names[(i+1)%len(names)]
+			break
+		}
+	}
+	if err := c.ApplyProfile(next); err != nil {
+		return "", err
+	}
+	return next, nil
+}
+
+// ExportProfile writes name's stored fields to path as TOML, for sharing a
+// profile between machines or backing one up outside config.json.
+func (c *Config) ExportProfile(name, path string) error {
+	p, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("config: no such profile %q", name)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return toml.NewEncoder(f).Encode(p)
+}
+
+// ImportProfile reads a TOML file previously written by ExportProfile and
+// stores it under name.
+func (c *Config) ImportProfile(name, path string) error {
+	var p Config
+	if _, err := toml.DecodeFile(path, &p); err != nil {
+		return err
+	}
+	if c.Profiles == nil {
+		c.Profiles = map[string]Config{}
+	}
+	c.Profiles[name] = p
+	return nil
+}
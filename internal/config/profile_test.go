@@ -0,0 +1,141 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func newTestConfig() *Config {
+	cfg := Default()
+	cfg.Language = "en-US"
+	cfg.Sensitivity = 2.5
+	return cfg
+}
+
+func TestSaveAndApplyProfile(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.SaveProfile("english")
+
+	cfg.Language = "fa-IR"
+	cfg.Sensitivity = 4.0
+	cfg.SaveProfile("persian")
+
+	if err := cfg.ApplyProfile("english"); err != nil {
+		t.Fatalf("ApplyProfile(english): %v", err)
+	}
+	if cfg.Language != "en-US" || cfg.Sensitivity != 2.5 {
+		t.Errorf("after ApplyProfile(english): language=%q sensitivity=%f", cfg.Language, cfg.Sensitivity)
+	}
+	if cfg.ActiveProfile != "english" {
+		t.Errorf("ActiveProfile = %q, want %q", cfg.ActiveProfile, "english")
+	}
+
+	if err := cfg.ApplyProfile("persian"); err != nil {
+		t.Fatalf("ApplyProfile(persian): %v", err)
+	}
+	if cfg.Language != "fa-IR" || cfg.Sensitivity != 4.0 {
+		t.Errorf("after ApplyProfile(persian): language=%q sensitivity=%f", cfg.Language, cfg.Sensitivity)
+	}
+}
+
+func TestApplyProfileUnknownName(t *testing.T) {
+	cfg := newTestConfig()
+	if err := cfg.ApplyProfile("missing"); err == nil {
+		t.Error("ApplyProfile should fail for an unknown profile")
+	}
+}
+
+func TestApplyProfileLeavesHotkeysAlone(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Hotkey = "Alt-d"
+	cfg.SaveProfile("english")
+	cfg.Hotkey = "Ctrl-Alt-d"
+
+	if err := cfg.ApplyProfile("english"); err != nil {
+		t.Fatalf("ApplyProfile: %v", err)
+	}
+	if cfg.Hotkey != "Ctrl-Alt-d" {
+		t.Errorf("Hotkey = %q, want ApplyProfile to leave it untouched", cfg.Hotkey)
+	}
+}
+
+func TestDuplicateAndDeleteProfile(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.SaveProfile("english")
+
+	if err := cfg.DuplicateProfile("english", "english-copy"); err != nil {
+		t.Fatalf("DuplicateProfile: %v", err)
+	}
+	if _, ok := cfg.Profiles["english-copy"]; !ok {
+		t.Fatal("DuplicateProfile did not create english-copy")
+	}
+
+	cfg.ActiveProfile = "english"
+	cfg.DeleteProfile("english")
+	if _, ok := cfg.Profiles["english"]; ok {
+		t.Error("DeleteProfile did not remove the entry")
+	}
+	if cfg.ActiveProfile != "" {
+		t.Errorf("ActiveProfile = %q, want cleared after deleting the active profile", cfg.ActiveProfile)
+	}
+}
+
+func TestCycleProfile(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.SaveProfile("b-profile")
+	cfg.SaveProfile("a-profile")
+	cfg.ActiveProfile = "a-profile"
+
+	next, err := cfg.CycleProfile()
+	if err != nil {
+		t.Fatalf("CycleProfile: %v", err)
+	}
+	if next != "b-profile" {
+		t.Errorf("CycleProfile() = %q, want %q", next, "b-profile")
+	}
+
+	next, err = cfg.CycleProfile()
+	if err != nil {
+		t.Fatalf("CycleProfile: %v", err)
+	}
+	if next != "a-profile" {
+		t.Errorf("CycleProfile() = %q, want wraparound to %q", next, "a-profile")
+	}
+}
+
+func TestCycleProfileWithFewerThanTwoIsNoop(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.SaveProfile("only")
+	cfg.ActiveProfile = "only"
+
+	next, err := cfg.CycleProfile()
+	if err != nil {
+		t.Fatalf("CycleProfile: %v", err)
+	}
+	if next != "" {
+		t.Errorf("CycleProfile() = %q, want \"\" with a single profile", next)
+	}
+}
+
+func TestExportImportProfileRoundTrip(t *testing.T) {
+	cfg := newTestConfig()
+	cfg.Language = "fa-IR"
+	cfg.Sensitivity = 3.5
+	cfg.SaveProfile("persian")
+
+	path := filepath.Join(t.TempDir(), "persian.toml")
+	if err := cfg.ExportProfile("persian", path); err != nil {
+		t.Fatalf("ExportProfile: %v", err)
+	}
+
+	other := newTestConfig()
+	if err := other.ImportProfile("persian", path); err != nil {
+		t.Fatalf("ImportProfile: %v", err)
+	}
+	if err := other.ApplyProfile("persian"); err != nil {
+		t.Fatalf("ApplyProfile after import: %v", err)
+	}
+	if other.Language != "fa-IR" || other.Sensitivity != 3.5 {
+		t.Errorf("imported profile = {language=%q sensitivity=%f}, want {fa-IR 3.5}", other.Language, other.Sensitivity)
+	}
+}
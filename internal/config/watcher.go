@@ -0,0 +1,139 @@
+package config
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounceDelay coalesces the burst of fsnotify events a single editor save
+// produces (e.g. write-then-rename, or multiple writes) into one reload.
+const debounceDelay = 200 * time.Millisecond
+
+// Watcher reloads config.json whenever it changes on disk and delivers
+// debounced snapshots over Changes().
+type Watcher struct {
+	fsw     *fsnotify.Watcher
+	changes chan *Config
+	done    chan struct{}
+}
+
+// NewWatcher watches the config directory (not the file itself, so editors
+// that save via rename-into-place are handled) and starts delivering
+// snapshots. The initial config passed to Load() is used as the baseline:
+// only configs that differ from it in a meaningful field are emitted.
+func NewWatcher(initial *Config) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fsw.Add(dir()); err != nil {
+		fsw.Close() //nolint:errcheck
+		return nil, err
+	}
+
+	w := &Watcher{
+		fsw:     fsw,
+		changes: make(chan *Config),
+		done:    make(chan struct{}),
+	}
+	go w.run(initial)
+	return w, nil
+}
+
+// Changes returns a channel of debounced *Config snapshots. A value is sent
+// only when Load() produces a config that differs meaningfully from the
+// last one delivered (or from the initial config passed to NewWatcher).
+func (w *Watcher) Changes() <-chan *Config {
+	return w.changes
+}
+
+// Close stops the underlying fsnotify watcher and closes the Changes channel.
+func (w *Watcher) Close() error {
+	close(w.done)
+	err := w.fsw.Close()
+	return err
+}
+
+func (w *Watcher) run(last *Config) {
+	defer close(w.changes)
+
+	var debounce *time.Timer
+	var debounceC <-chan time.Time
+
+	resetDebounce := func() {
+		if debounce == nil {
+			debounce = time.NewTimer(debounceDelay)
+		} else {
+			if !debounce.Stop() {
+				select {
+				case <-debounce.C:
+				default:
+				}
+			}
+			debounce.Reset(debounceDelay)
+		}
+		debounceC = debounce.C
+	}
+
+	cfgPath := path()
+
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			// Only events about config.json itself are interesting; the
+			// directory may contain other files (or none yet).
+			if filepath.Clean(ev.Name) != cfgPath {
+				continue
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			resetDebounce()
+
+		case <-debounceC:
+			debounceC = nil
+			cfg, err := Load()
+			if err != nil {
+				continue
+			}
+			if changed(last, cfg) {
+				last = cfg
+				select {
+				case w.changes <- cfg:
+				case <-w.done:
+					return
+				}
+			}
+
+		case <-w.fsw.Errors:
+			// Best-effort: keep watching even if fsnotify reports a transient error.
+		}
+	}
+}
+
+// changed reports whether any field a live subscriber cares about differs
+// between a and b.
+func changed(a, b *Config) bool {
+	return a.Hotkey != b.Hotkey ||
+		a.UndoHotkey != b.UndoHotkey ||
+		a.PTTHotkey != b.PTTHotkey ||
+		a.Language != b.Language ||
+		a.Timeout != b.Timeout ||
+		a.SilenceChunks != b.SilenceChunks ||
+		a.Sensitivity != b.Sensitivity ||
+		a.APIKey != b.APIKey ||
+		a.UseAdvancedAPI != b.UseAdvancedAPI ||
+		a.Backend != b.Backend ||
+		a.WhisperModelPath != b.WhisperModelPath ||
+		a.WhisperModelSize != b.WhisperModelSize ||
+		a.WhisperThreads != b.WhisperThreads ||
+		a.EnablePunctuation != b.EnablePunctuation
+}
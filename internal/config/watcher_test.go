@@ -0,0 +1,178 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setupWatcherHome points HOME at a fresh temp dir, creates the config dir,
+// and writes an initial config.json so NewWatcher has a stable baseline.
+func setupWatcherHome(t *testing.T) (home string, cfgPath string, initial *Config) {
+	t.Helper()
+	home = t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfgDir := filepath.Join(home, ".config", "gotalk-dictation")
+	if err := os.MkdirAll(cfgDir, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	cfgPath = filepath.Join(cfgDir, "config.json")
+
+	initial = Default()
+	if err := initial.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return home, cfgPath, initial
+}
+
+// awaitOneChange waits for exactly one config on ch, then asserts no second
+// one follows within a further debounce window.
+func awaitOneChange(t *testing.T, ch <-chan *Config) *Config {
+	t.Helper()
+	select {
+	case cfg, ok := <-ch:
+		if !ok {
+			t.Fatal("Changes() channel closed unexpectedly")
+		}
+		select {
+		case extra, ok := <-ch:
+			if ok {
+				t.Fatalf("got a second update, want exactly one: %+v", extra)
+			}
+		case <-time.After(debounceDelay * 2):
+		}
+		return cfg
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for config change")
+		return nil
+	}
+}
+
+func TestWatcherFiresOnWrite(t *testing.T) {
+	_, cfgPath, initial := setupWatcherHome(t)
+
+	w, err := NewWatcher(initial)
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+	defer w.Close()
+
+	cfg := Default()
+	cfg.Language = "fr-FR"
+	if err := os.WriteFile(cfgPath, mustMarshal(t, cfg), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	got := awaitOneChange(t, w.Changes())
+	if got.Language != "fr-FR" {
+		t.Errorf("Language = %q, want %q", got.Language, "fr-FR")
+	}
+}
+
+func TestWatcherFiresOnAtomicRename(t *testing.T) {
+	home, cfgPath, initial := setupWatcherHome(t)
+
+	w, err := NewWatcher(initial)
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+	defer w.Close()
+
+	cfg := Default()
+	cfg.Hotkey = "Ctrl-Alt-d"
+	tmpPath := filepath.Join(home, ".config", "gotalk-dictation", "config.json.tmp")
+	if err := os.WriteFile(tmpPath, mustMarshal(t, cfg), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.Rename(tmpPath, cfgPath); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	got := awaitOneChange(t, w.Changes())
+	if got.Hotkey != "Ctrl-Alt-d" {
+		t.Errorf("Hotkey = %q, want %q", got.Hotkey, "Ctrl-Alt-d")
+	}
+}
+
+func TestWatcherFiresOnTruncateAndRewrite(t *testing.T) {
+	_, cfgPath, initial := setupWatcherHome(t)
+
+	w, err := NewWatcher(initial)
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+	defer w.Close()
+
+	f, err := os.OpenFile(cfgPath, os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	cfg := Default()
+	cfg.Sensitivity = 4.0
+	if _, err := f.Write(mustMarshal(t, cfg)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	f.Close() //nolint:errcheck
+
+	got := awaitOneChange(t, w.Changes())
+	if got.Sensitivity != 4.0 {
+		t.Errorf("Sensitivity = %v, want 4.0", got.Sensitivity)
+	}
+}
+
+func TestWatcherIgnoresNoOpRewrite(t *testing.T) {
+	_, cfgPath, initial := setupWatcherHome(t)
+
+	w, err := NewWatcher(initial)
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+	defer w.Close()
+
+	// Rewrite the file with identical contents; no field actually changed.
+	if err := os.WriteFile(cfgPath, mustMarshal(t, initial), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	select {
+	case cfg, ok := <-w.Changes():
+		if ok {
+			t.Fatalf("got unexpected update for a no-op rewrite: %+v", cfg)
+		}
+	case <-time.After(debounceDelay * 2):
+		// No update fired, as expected.
+	}
+}
+
+func TestWatcherCloseClosesChannel(t *testing.T) {
+	_, _, initial := setupWatcherHome(t)
+
+	w, err := NewWatcher(initial)
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	select {
+	case _, ok := <-w.Changes():
+		if ok {
+			t.Fatal("expected Changes() to be closed after Close()")
+		}
+	case <-time.After(1 * time.Second):
+		t.Fatal("Changes() did not close within 1s of Close()")
+	}
+}
+
+func mustMarshal(t *testing.T, cfg *Config) []byte {
+	t.Helper()
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return data
+}
@@ -0,0 +1,125 @@
+// Package events emits a newline-delimited JSON record for every meaningful
+// dictation lifecycle event, so editors, tmux status lines, or other
+// external tooling can script against the daemon instead of parsing logs.
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Event is a single record in the stream. Only the fields relevant to Type
+// are populated; the rest are omitted from the JSON output.
+type Event struct {
+	Type string `json:"type"`
+	Ts   int64  `json:"ts"` // unix millis
+
+	DurationMs int64   `json:"duration_ms,omitempty"`
+	RMS        float64 `json:"rms,omitempty"`
+	Backend    string  `json:"backend,omitempty"`
+	Text       string  `json:"text,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+	LatencyMs  int64   `json:"latency_ms,omitempty"`
+	RuneCount  int     `json:"rune_count,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Emitter serializes lifecycle events as newline-delimited JSON under a
+// mutex, modeled on the stdlib test2json converter: one writer, one record
+// per line, safely closable. A nil *Emitter discards every event, so
+// callers never need to check whether event emission is enabled.
+type Emitter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closed bool
+}
+
+// NewEmitter wraps w, writing one JSON object per line to it.
+func NewEmitter(w io.Writer) *Emitter {
+	return &Emitter{w: w}
+}
+
+func (e *Emitter) emit(ev Event) {
+	if e == nil {
+		return
+	}
+	ev.Ts = time.Now().UnixMilli()
+
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return
+	}
+	e.w.Write(data) //nolint:errcheck // best-effort stream; a stalled reader must never block dictation
+}
+
+// RecordingStart records that the mic was just opened.
+func (e *Emitter) RecordingStart() { e.emit(Event{Type: "recording_start"}) }
+
+// VADSpeech records that the VAD transitioned from silence into speech.
+func (e *Emitter) VADSpeech() { e.emit(Event{Type: "vad_speech"}) }
+
+// VADSilence records that the VAD detected end-of-phrase silence.
+func (e *Emitter) VADSilence() { e.emit(Event{Type: "vad_silence"}) }
+
+// Chunk records one audio chunk observed by the VAD.
+func (e *Emitter) Chunk(durationMs int64, rms float64) {
+	e.emit(Event{Type: "chunk", DurationMs: durationMs, RMS: rms})
+}
+
+// RecognitionRequest records that buffered audio is being sent to backend.
+func (e *Emitter) RecognitionRequest(backend string) {
+	e.emit(Event{Type: "recognition_request", Backend: backend})
+}
+
+// RecognitionResult records a completed transcription.
+func (e *Emitter) RecognitionResult(text string, confidence float64, latencyMs int64, backend string) {
+	e.emit(Event{
+		Type:       "recognition_result",
+		Text:       text,
+		Confidence: confidence,
+		LatencyMs:  latencyMs,
+		Backend:    backend,
+	})
+}
+
+// Typed records that text was typed into the focused window.
+func (e *Emitter) Typed(runeCount int) { e.emit(Event{Type: "typed", RuneCount: runeCount}) }
+
+// Undo records that the last typed text was undone.
+func (e *Emitter) Undo() { e.emit(Event{Type: "undo"}) }
+
+// Error records a non-fatal error encountered during dictation.
+func (e *Emitter) Error(err error) {
+	if err == nil {
+		return
+	}
+	e.emit(Event{Type: "error", Error: err.Error()})
+}
+
+// Close flushes a final "exited" record and closes the underlying writer if
+// it implements io.Closer. Safe to call once; safe on a nil *Emitter.
+func (e *Emitter) Close() error {
+	if e == nil {
+		return nil
+	}
+	e.emit(Event{Type: "exited"})
+
+	e.mu.Lock()
+	e.closed = true
+	w := e.w
+	e.mu.Unlock()
+
+	if c, ok := w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []Event {
+	t.Helper()
+	var evs []Event
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("unmarshal event: %v", err)
+		}
+		evs = append(evs, ev)
+	}
+	return evs
+}
+
+func TestEmitterWritesNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEmitter(&buf)
+
+	e.RecordingStart()
+	e.VADSpeech()
+	e.Chunk(62, 1234.5)
+	e.RecognitionResult("hello world", 0.9, 150, "google-free")
+
+	evs := decodeLines(t, &buf)
+	if len(evs) != 4 {
+		t.Fatalf("got %d events, want 4", len(evs))
+	}
+	if evs[0].Type != "recording_start" {
+		t.Errorf("evs[0].Type = %q, want recording_start", evs[0].Type)
+	}
+	if evs[3].Text != "hello world" || evs[3].Backend != "google-free" {
+		t.Errorf("evs[3] = %+v, want text/backend populated", evs[3])
+	}
+	for _, ev := range evs {
+		if ev.Ts == 0 {
+			t.Errorf("event %q has zero timestamp", ev.Type)
+		}
+	}
+}
+
+func TestEmitterNilReceiverIsNoop(t *testing.T) {
+	var e *Emitter
+	e.RecordingStart()
+	e.VADSpeech()
+	e.Chunk(1, 1)
+	e.Typed(3)
+	e.Error(nil)
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close on nil Emitter: %v", err)
+	}
+}
+
+func TestEmitterCloseWritesExitedAndStopsFurtherWrites(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEmitter(&buf)
+	e.RecordingStart()
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	e.Typed(5) // should be dropped, Emitter is closed
+
+	evs := decodeLines(t, &buf)
+	if len(evs) != 2 {
+		t.Fatalf("got %d events, want 2 (recording_start, exited)", len(evs))
+	}
+	if evs[1].Type != "exited" {
+		t.Errorf("evs[1].Type = %q, want exited", evs[1].Type)
+	}
+}
@@ -0,0 +1,73 @@
+package events
+
+import (
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// writeTimeout bounds how long Write waits on one client before dropping it.
+const writeTimeout = 100 * time.Millisecond
+
+// Broadcaster is an io.Writer that fans every Write out to all currently
+// connected clients of a Unix socket, so a GUI frontend (or several) can
+// attach to the event stream without racing with stdout. A connection that
+// errors or falls behind is dropped rather than allowed to block the
+// stream.
+type Broadcaster struct {
+	ln net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// Listen starts accepting connections on socketPath, removing any stale
+// socket file left behind by a previous run.
+func Listen(socketPath string) (*Broadcaster, error) {
+	os.Remove(socketPath) //nolint:errcheck
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	b := &Broadcaster{ln: ln, conns: make(map[net.Conn]struct{})}
+	go b.acceptLoop()
+	return b, nil
+}
+
+func (b *Broadcaster) acceptLoop() {
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		b.conns[conn] = struct{}{}
+		b.mu.Unlock()
+	}
+}
+
+// Write implements io.Writer, sending p to every connected client.
+func (b *Broadcaster) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for conn := range b.conns {
+		conn.SetWriteDeadline(time.Now().Add(writeTimeout)) //nolint:errcheck
+		if _, err := conn.Write(p); err != nil {
+			conn.Close() //nolint:errcheck
+			delete(b.conns, conn)
+		}
+	}
+	return len(p), nil
+}
+
+// Close disconnects every client and stops accepting new connections.
+func (b *Broadcaster) Close() error {
+	b.mu.Lock()
+	for conn := range b.conns {
+		conn.Close() //nolint:errcheck
+		delete(b.conns, conn)
+	}
+	b.mu.Unlock()
+	return b.ln.Close()
+}
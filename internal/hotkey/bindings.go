@@ -0,0 +1,464 @@
+// Package hotkey grabs global keyboard shortcuts and dispatches
+// press/release/tap/hold callbacks for them, over whichever backend fits the
+// session: X11Bindings (direct XGrabKey, for Xorg and XWayland) or
+// WaylandBindings (the freedesktop GlobalShortcuts portal, for native
+// Wayland compositors where nothing may grab another client's keys).
+package hotkey
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// defaultHoldThreshold is how long a key must be held before it counts as a
+// hold rather than a tap, when an Action doesn't set HoldThreshold itself.
+const defaultHoldThreshold = 250 * time.Millisecond
+
+// autoRepeatGrace is how long the event loop waits after a KeyRelease before
+// treating it as a real release. X11 auto-repeat sends a KeyRelease+KeyPress
+// pair in rapid succession while a key is held down; if a matching KeyPress
+// arrives within this window, the release was auto-repeat, not a real
+// key-up.
+const autoRepeatGrace = 50 * time.Millisecond
+
+// relevantModMask is the set of modifier bits Bindings cares about when
+// routing an incoming event to a binding. grab() additionally grabs every
+// combination of NumLock/CapsLock (ModMask2/ModMaskLock) so the hotkey works
+// regardless of lock-key state; those bits are stripped from both the grab
+// modMask and the event's State before comparing, so all four physical grabs
+// for one chord route to the same binding.
+const relevantModMask = uint16(xproto.ModMaskShift | xproto.ModMaskControl | xproto.ModMask1 | xproto.ModMask4)
+
+// Action describes how a single hotkey reacts to being pressed and released.
+//
+// On KeyPress, OnPress fires immediately (if set) and, if OnTap or OnHold is
+// set, a HoldThreshold timer starts. If the key is released before the timer
+// fires, OnTap runs; otherwise OnHold runs when the timer elapses and
+// OnRelease runs at the real key-up. Any field may be left nil. Set OnPress
+// alone for the classic toggle behavior (fire once per press, ignore
+// auto-repeat); pair OnPress with OnRelease for push-to-talk; set OnTap and
+// OnHold (with or without OnRelease) to make one chord do double duty, e.g.
+// tap to toggle, hold for push-to-talk.
+type Action struct {
+	OnPress       func()
+	OnRelease     func()
+	OnTap         func()
+	OnHold        func()
+	HoldThreshold time.Duration // zero means defaultHoldThreshold (250ms)
+}
+
+func (a Action) holdThreshold() time.Duration {
+	if a.HoldThreshold > 0 {
+		return a.HoldThreshold
+	}
+	return defaultHoldThreshold
+}
+
+// wantsReleaseTracking reports whether anything about this Action cares
+// about how/when the key comes back up, so the event loop knows whether to
+// pay the auto-repeat-grace cost on every release.
+func (a Action) wantsReleaseTracking() bool {
+	return a.OnRelease != nil || a.OnTap != nil || a.OnHold != nil
+}
+
+// chord identifies a grabbed hotkey by its X11 keycode and the (masked)
+// modifiers it was bound with, so an incoming event can be routed to the
+// right binding even when several chords share a keycode (e.g. Super+Space
+// and Super+Shift+Space).
+type chord struct {
+	keycode xproto.Keycode
+	modMask uint16
+}
+
+// dispatcher pairs an Action with the press/release/hold state the event
+// loop tracks for it. Every field here is atomic: the hold timer and
+// release-grace goroutines read and write them from outside the event-loop
+// goroutine.
+type dispatcher struct {
+	action Action
+
+	pressed        atomic.Bool
+	pendingRelease atomic.Bool
+	releaseGen     atomic.Uint64
+	holdGen        atomic.Uint64
+	holdFired      atomic.Bool
+}
+
+// press runs the tap/hold state machine for a KeyPress/Activated event.
+func (d *dispatcher) press() {
+	if d.action.wantsReleaseTracking() && d.pendingRelease.Swap(false) {
+		// KeyPress while the release-grace timer is still pending = auto-repeat.
+		// Cancel the pending release so its goroutine won't fire anything.
+		d.releaseGen.Add(1)
+		d.pressed.Store(true)
+		return
+	}
+	if d.pressed.Load() {
+		return
+	}
+	d.pressed.Store(true)
+
+	if d.action.OnPress != nil {
+		go d.action.OnPress()
+	}
+
+	if d.action.OnTap != nil || d.action.OnHold != nil {
+		d.holdFired.Store(false)
+		gen := d.holdGen.Add(1)
+		threshold := d.action.holdThreshold()
+		action := d.action
+		go func() {
+			time.Sleep(threshold)
+			if d.holdGen.Load() != gen {
+				return // tapped (or re-pressed) before the threshold fired
+			}
+			d.holdFired.Store(true)
+			if action.OnHold != nil {
+				action.OnHold()
+			}
+		}()
+	}
+}
+
+// release runs the tap/hold state machine for a KeyRelease/Deactivated
+// event. On X11 this is gated behind autoRepeatGrace to filter out
+// auto-repeat; portal backends have no auto-repeat artifact to filter, so
+// they call this directly once the compositor's own Deactivated arrives.
+func (d *dispatcher) release() {
+	d.pressed.Store(false)
+	if !d.action.wantsReleaseTracking() {
+		return
+	}
+
+	if d.action.OnTap != nil || d.action.OnHold != nil {
+		if d.holdFired.Load() {
+			if d.action.OnRelease != nil {
+				d.action.OnRelease()
+			}
+		} else {
+			d.holdGen.Add(1) // cancel the still-pending hold timer: this was a tap
+			if d.action.OnTap != nil {
+				d.action.OnTap()
+			}
+		}
+		return
+	}
+	if d.action.OnRelease != nil {
+		d.action.OnRelease()
+	}
+}
+
+// Bindings multiplexes any number of named hotkeys — grabbing them,
+// rebinding them as the user edits settings, and dispatching their
+// press/release/tap/hold callbacks — over whichever backend NewBindings
+// picked for the current session.
+type Bindings interface {
+	// Bind parses hotkey, grabs it, and wires action to it. Rebinding the
+	// same hotkey string replaces its action without re-grabbing.
+	Bind(hotkey string, action Action) error
+	// Unbind ungrabs hotkey and removes its action. It's a no-op if hotkey
+	// was never bound.
+	Unbind(hotkey string) error
+	// Stop ungrabs every hotkey and releases the backend's connection.
+	Stop()
+}
+
+// NewBindings opens the hotkey backend for the current session: Wayland
+// Bindings under a native Wayland session ($WAYLAND_DISPLAY set, or
+// $XDG_SESSION_TYPE=wayland), falling back to X11Bindings (XWayland or
+// Xorg) otherwise, or if the GlobalShortcuts portal isn't available.
+func NewBindings() (Bindings, error) {
+	if isWaylandSession() {
+		if w, err := newWaylandBindings(); err == nil {
+			return w, nil
+		}
+	}
+	x, err := newX11Bindings()
+	if err != nil {
+		return nil, err // a typed-nil *X11Bindings here would satisfy a Bindings != nil check wrongly
+	}
+	return x, nil
+}
+
+func isWaylandSession() bool {
+	return os.Getenv("WAYLAND_DISPLAY") != "" || strings.EqualFold(os.Getenv("XDG_SESSION_TYPE"), "wayland")
+}
+
+// X11Bindings multiplexes any number of hotkeys across a single X11
+// connection and event loop, so registering several chords doesn't open one
+// X11 connection per hotkey.
+type X11Bindings struct {
+	conn *xgb.Conn
+
+	mu       sync.Mutex
+	bindings map[chord]*dispatcher
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// newX11Bindings opens an X11 connection and starts its event loop with
+// nothing bound yet.
+func newX11Bindings() (*X11Bindings, error) {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to X11: %w", err)
+	}
+
+	b := &X11Bindings{
+		conn:     conn,
+		bindings: make(map[chord]*dispatcher),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+	go b.eventLoop()
+	return b, nil
+}
+
+func (b *X11Bindings) Bind(hotkey string, action Action) error {
+	keycode, modMask, err := parseHotkey(b.conn, hotkey)
+	if err != nil {
+		return fmt.Errorf("parsing hotkey %q: %w", hotkey, err)
+	}
+
+	c := chord{keycode: keycode, modMask: modMask & relevantModMask}
+
+	b.mu.Lock()
+	_, already := b.bindings[c]
+	b.mu.Unlock()
+	if !already {
+		if err := grab(b.conn, keycode, modMask); err != nil {
+			return err
+		}
+	}
+
+	b.mu.Lock()
+	b.bindings[c] = &dispatcher{action: action}
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *X11Bindings) Unbind(hotkey string) error {
+	keycode, modMask, err := parseHotkey(b.conn, hotkey)
+	if err != nil {
+		return fmt.Errorf("parsing hotkey %q: %w", hotkey, err)
+	}
+
+	c := chord{keycode: keycode, modMask: modMask & relevantModMask}
+	b.mu.Lock()
+	_, ok := b.bindings[c]
+	delete(b.bindings, c)
+	b.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return ungrab(b.conn, keycode, modMask)
+}
+
+// Stop ungrabs every hotkey, tears down the event loop, and closes the X11
+// connection.
+func (b *X11Bindings) Stop() {
+	select {
+	case <-b.stopCh:
+	default:
+		close(b.stopCh)
+	}
+	<-b.doneCh
+	b.conn.Close()
+}
+
+func (b *X11Bindings) eventLoop() {
+	defer close(b.doneCh)
+
+	for {
+		select {
+		case <-b.stopCh:
+			return
+		default:
+		}
+
+		ev, err := b.conn.WaitForEvent()
+		if err != nil || ev == nil {
+			return
+		}
+
+		switch e := ev.(type) {
+		case xproto.KeyPressEvent:
+			b.handlePress(chord{keycode: e.Detail, modMask: uint16(e.State) & relevantModMask})
+		case xproto.KeyReleaseEvent:
+			b.handleRelease(chord{keycode: e.Detail, modMask: uint16(e.State) & relevantModMask})
+		}
+	}
+}
+
+func (b *X11Bindings) handlePress(c chord) {
+	b.mu.Lock()
+	d := b.bindings[c]
+	b.mu.Unlock()
+	if d == nil {
+		return
+	}
+	d.press()
+}
+
+// handleRelease gates the dispatcher's release() behind autoRepeatGrace: X11
+// auto-repeat sends a KeyRelease+KeyPress pair in rapid succession while a
+// key is held down, so a raw KeyRelease isn't trusted until no matching
+// KeyPress shows up within the grace window.
+func (b *X11Bindings) handleRelease(c chord) {
+	b.mu.Lock()
+	d := b.bindings[c]
+	b.mu.Unlock()
+	if d == nil {
+		return
+	}
+
+	if !d.action.wantsReleaseTracking() {
+		d.pressed.Store(false)
+		return
+	}
+
+	d.pendingRelease.Store(true)
+	gen := d.releaseGen.Add(1)
+	go func() {
+		time.Sleep(autoRepeatGrace)
+		d.pendingRelease.Store(false)
+		if d.releaseGen.Load() != gen {
+			return // an auto-repeat press arrived; this wasn't a real release
+		}
+		d.release()
+	}()
+}
+
+func grab(conn *xgb.Conn, keycode xproto.Keycode, modMask uint16) error {
+	root := xproto.Setup(conn).DefaultScreen(conn).Root
+
+	// Grab with NumLock/CapsLock modifier combinations so the hotkey works
+	// regardless of lock key state.
+	extras := []uint16{0, uint16(xproto.ModMask2), uint16(xproto.ModMaskLock), uint16(xproto.ModMask2) | uint16(xproto.ModMaskLock)}
+	for _, extra := range extras {
+		mod := modMask | extra
+		if err := xproto.GrabKeyChecked(conn, true, root, mod, keycode,
+			xproto.GrabModeAsync, xproto.GrabModeAsync).Check(); err != nil {
+			return fmt.Errorf("grabbing key (mod=%d): %w", mod, err)
+		}
+	}
+	return nil
+}
+
+func ungrab(conn *xgb.Conn, keycode xproto.Keycode, modMask uint16) error {
+	root := xproto.Setup(conn).DefaultScreen(conn).Root
+
+	extras := []uint16{0, uint16(xproto.ModMask2), uint16(xproto.ModMaskLock), uint16(xproto.ModMask2) | uint16(xproto.ModMaskLock)}
+	for _, extra := range extras {
+		mod := modMask | extra
+		if err := xproto.UngrabKeyChecked(conn, keycode, root, mod).Check(); err != nil {
+			return fmt.Errorf("ungrabbing key (mod=%d): %w", mod, err)
+		}
+	}
+	return nil
+}
+
+func parseHotkey(conn *xgb.Conn, hotkey string) (xproto.Keycode, uint16, error) {
+	parts := strings.FieldsFunc(hotkey, func(r rune) bool {
+		return r == '+' || r == '-'
+	})
+
+	var modMask uint16
+	var keyName string
+
+	for _, p := range parts {
+		switch strings.ToLower(p) {
+		case "alt", "mod1":
+			modMask |= uint16(xproto.ModMask1)
+		case "ctrl", "control":
+			modMask |= uint16(xproto.ModMaskControl)
+		case "shift":
+			modMask |= uint16(xproto.ModMaskShift)
+		case "super", "mod4", "win":
+			modMask |= uint16(xproto.ModMask4)
+		default:
+			keyName = p
+		}
+	}
+
+	if keyName == "" {
+		return 0, 0, fmt.Errorf("no key specified")
+	}
+
+	keycode, err := findKeycode(conn, keyName)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return keycode, modMask, nil
+}
+
+func findKeycode(conn *xgb.Conn, keyName string) (xproto.Keycode, error) {
+	setup := xproto.Setup(conn)
+	min := setup.MinKeycode
+	max := setup.MaxKeycode
+
+	km, err := xproto.GetKeyboardMapping(conn, min, byte(max-min+1)).Reply()
+	if err != nil {
+		return 0, fmt.Errorf("getting keyboard mapping: %w", err)
+	}
+
+	var targetKeysym uint32
+	if len(keyName) == 1 {
+		targetKeysym = uint32(keyName[0])
+	} else {
+		switch strings.ToLower(keyName) {
+		case "space":
+			targetKeysym = 0x0020
+		case "return", "enter":
+			targetKeysym = 0xff0d
+		case "escape", "esc":
+			targetKeysym = 0xff1b
+		case "tab":
+			targetKeysym = 0xff09
+		case "f1":
+			targetKeysym = 0xffbe
+		case "f2":
+			targetKeysym = 0xffbf
+		case "f3":
+			targetKeysym = 0xffc0
+		case "f4":
+			targetKeysym = 0xffc1
+		case "f5":
+			targetKeysym = 0xffc2
+		case "f6":
+			targetKeysym = 0xffc3
+		case "f7":
+			targetKeysym = 0xffc4
+		case "f8":
+			targetKeysym = 0xffc5
+		case "f9":
+			targetKeysym = 0xffc6
+		case "f10":
+			targetKeysym = 0xffc7
+		case "f11":
+			targetKeysym = 0xffc8
+		case "f12":
+			targetKeysym = 0xffc9
+		default:
+			return 0, fmt.Errorf("unknown key name: %q", keyName)
+		}
+	}
+
+	keysymsPerKeycode := int(km.KeysymsPerKeycode)
+	for i, keysym := range km.Keysyms {
+		if uint32(keysym) == targetKeysym {
+			return min + xproto.Keycode(i/keysymsPerKeycode), nil
+		}
+	}
+
+	return 0, fmt.Errorf("key %q not found in keyboard mapping", keyName)
+}
@@ -0,0 +1,301 @@
+package hotkey
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Portal bus name, object path, and interfaces for
+// org.freedesktop.portal.GlobalShortcuts, the Wayland-safe replacement for
+// XGrabKey: a compositor-mediated session that reports Activated/Deactivated
+// signals for shortcuts the user has assigned, since no Wayland protocol
+// lets one client grab another's keys.
+const (
+	portalBusName    = "org.freedesktop.portal.Desktop"
+	portalObjectPath = dbus.ObjectPath("/org/freedesktop/portal/desktop")
+	portalShortcuts  = "org.freedesktop.portal.GlobalShortcuts"
+	portalRequest    = "org.freedesktop.portal.Request"
+)
+
+// portalResponseTimeout bounds how long Bind/Unbind/newWaylandBindings wait
+// for the portal's async Request.Response signal before giving up.
+const portalResponseTimeout = 5 * time.Second
+
+// WaylandBindings implements Bindings over the GlobalShortcuts portal, for
+// native Wayland compositors (GNOME, KDE) that support it.
+type WaylandBindings struct {
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+
+	mu   sync.Mutex
+	byID map[string]*dispatcher // shortcut id == the hotkey string it binds
+
+	sigCh  chan *dbus.Signal
+	stopCh chan struct{}
+}
+
+// newWaylandBindings opens a session-bus connection, creates a
+// GlobalShortcuts session, and starts listening for Activated/Deactivated
+// signals. It fails if the portal isn't available, so NewBindings can fall
+// back to X11Bindings.
+func newWaylandBindings() (*WaylandBindings, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to session bus: %w", err)
+	}
+
+	session, err := createShortcutsSession(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	sigCh := make(chan *dbus.Signal, 16)
+	conn.Signal(sigCh)
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchInterface(portalShortcuts),
+		dbus.WithMatchObjectPath(portalObjectPath),
+	); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("subscribing to shortcut signals: %w", err)
+	}
+
+	w := &WaylandBindings{
+		conn:    conn,
+		session: session,
+		byID:    make(map[string]*dispatcher),
+		sigCh:   sigCh,
+		stopCh:  make(chan struct{}),
+	}
+	go w.eventLoop()
+	return w, nil
+}
+
+// createShortcutsSession calls GlobalShortcuts.CreateSession and waits for
+// the session_handle it reports back via a Request.Response signal.
+func createShortcutsSession(conn *dbus.Conn) (dbus.ObjectPath, error) {
+	token := fmt.Sprintf("gotalk_%d", os.Getpid())
+	options := map[string]dbus.Variant{
+		"session_handle_token": dbus.MakeVariant(token),
+		"handle_token":         dbus.MakeVariant(token),
+	}
+
+	var requestPath dbus.ObjectPath
+	obj := conn.Object(portalBusName, portalObjectPath)
+	if err := obj.Call(portalShortcuts+".CreateSession", 0, options).Store(&requestPath); err != nil {
+		return "", fmt.Errorf("CreateSession: %w", err)
+	}
+
+	results, err := awaitPortalResponse(conn, requestPath)
+	if err != nil {
+		return "", fmt.Errorf("CreateSession: %w", err)
+	}
+	v, ok := results["session_handle"]
+	if !ok {
+		return "", fmt.Errorf("CreateSession: response missing session_handle")
+	}
+	s, ok := v.Value().(string)
+	if !ok {
+		return "", fmt.Errorf("CreateSession: session_handle was not a string")
+	}
+	return dbus.ObjectPath(s), nil
+}
+
+// awaitPortalResponse subscribes to one org.freedesktop.portal.Request
+// object's Response signal and returns its results dict. Every
+// GlobalShortcuts call follows this two-step pattern: the method itself
+// only returns a request handle, and the real result arrives asynchronously
+// as a signal on that handle once the compositor (and, the first time,
+// the user) has acted on it.
+func awaitPortalResponse(conn *dbus.Conn, requestPath dbus.ObjectPath) (map[string]dbus.Variant, error) {
+	ch := make(chan *dbus.Signal, 1)
+	conn.Signal(ch)
+	defer conn.RemoveSignal(ch)
+
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchInterface(portalRequest),
+		dbus.WithMatchObjectPath(requestPath),
+	); err != nil {
+		return nil, fmt.Errorf("awaiting portal response: %w", err)
+	}
+
+	select {
+	case sig := <-ch:
+		if sig.Name != portalRequest+".Response" || len(sig.Body) < 2 {
+			return nil, fmt.Errorf("unexpected portal signal %q", sig.Name)
+		}
+		results, ok := sig.Body[1].(map[string]dbus.Variant)
+		if !ok {
+			return nil, fmt.Errorf("portal response missing results")
+		}
+		return results, nil
+	case <-time.After(portalResponseTimeout):
+		return nil, fmt.Errorf("timed out waiting for portal response")
+	}
+}
+
+// portalShortcut is the (s, a{sv}) struct GlobalShortcuts.BindShortcuts
+// expects per shortcut: an id plus a properties dict.
+type portalShortcut struct {
+	ID    string
+	Props map[string]dbus.Variant
+}
+
+func (w *WaylandBindings) Bind(hotkey string, action Action) error {
+	w.mu.Lock()
+	w.byID[hotkey] = &dispatcher{action: action}
+	w.mu.Unlock()
+	return w.syncShortcuts()
+}
+
+func (w *WaylandBindings) Unbind(hotkey string) error {
+	w.mu.Lock()
+	_, ok := w.byID[hotkey]
+	delete(w.byID, hotkey)
+	w.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return w.syncShortcuts()
+}
+
+func (w *WaylandBindings) Stop() {
+	select {
+	case <-w.stopCh:
+	default:
+		close(w.stopCh)
+	}
+	w.conn.RemoveSignal(w.sigCh)
+	w.conn.Close()
+}
+
+// syncShortcuts re-issues BindShortcuts with every currently-registered
+// hotkey. The portal has no incremental unbind call, so a changed set is
+// synced in full each time; GNOME's and KDE's implementations both treat a
+// session's bound set as whatever the latest BindShortcuts call said it is.
+func (w *WaylandBindings) syncShortcuts() error {
+	w.mu.Lock()
+	specs := make([]portalShortcut, 0, len(w.byID))
+	for id := range w.byID {
+		specs = append(specs, portalShortcut{
+			ID: id,
+			Props: map[string]dbus.Variant{
+				"description":       dbus.MakeVariant(id),
+				"preferred_trigger": dbus.MakeVariant(portalTrigger(id)),
+			},
+		})
+	}
+	w.mu.Unlock()
+
+	options := map[string]dbus.Variant{
+		"handle_token": dbus.MakeVariant(fmt.Sprintf("gotalk_bind_%d", time.Now().UnixNano())),
+	}
+
+	var requestPath dbus.ObjectPath
+	obj := w.conn.Object(portalBusName, portalObjectPath)
+	if err := obj.Call(portalShortcuts+".BindShortcuts", 0, w.session, specs, "", options).Store(&requestPath); err != nil {
+		return fmt.Errorf("BindShortcuts: %w", err)
+	}
+	_, err := awaitPortalResponse(w.conn, requestPath)
+	return err
+}
+
+// portalTrigger converts a hotkey string in the same "mod+mod+key" syntax
+// parseHotkey accepts into the GTK accelerator syntax (e.g. "<Control><Alt>space")
+// GlobalShortcuts.BindShortcuts expects for preferred_trigger: the hint the
+// compositor shows the user and pre-selects the first time they're asked to
+// confirm a shortcut.
+func portalTrigger(hotkey string) string {
+	parts := strings.FieldsFunc(hotkey, func(r rune) bool {
+		return r == '+' || r == '-'
+	})
+
+	var mods strings.Builder
+	var keyName string
+	for _, p := range parts {
+		switch strings.ToLower(p) {
+		case "alt", "mod1":
+			mods.WriteString("<Alt>")
+		case "ctrl", "control":
+			mods.WriteString("<Control>")
+		case "shift":
+			mods.WriteString("<Shift>")
+		case "super", "mod4", "win":
+			mods.WriteString("<Super>")
+		default:
+			keyName = p
+		}
+	}
+	return mods.String() + portalKeyName(keyName)
+}
+
+// portalKeyName maps a parseHotkey-style key name to the GDK keyval name
+// gtk_accelerator_parse (and so the portal's own trigger parsing) expects.
+func portalKeyName(keyName string) string {
+	if len(keyName) == 1 {
+		return strings.ToLower(keyName)
+	}
+	switch strings.ToLower(keyName) {
+	case "space":
+		return "space"
+	case "return", "enter":
+		return "Return"
+	case "escape", "esc":
+		return "Escape"
+	case "tab":
+		return "Tab"
+	case "f1", "f2", "f3", "f4", "f5", "f6", "f7", "f8", "f9", "f10", "f11", "f12":
+		return strings.ToUpper(keyName)
+	default:
+		return keyName
+	}
+}
+
+func (w *WaylandBindings) eventLoop() {
+	for {
+		select {
+		case <-w.stopCh:
+			return
+		case sig, ok := <-w.sigCh:
+			if !ok {
+				return
+			}
+			switch sig.Name {
+			case portalShortcuts + ".Activated":
+				w.dispatch(sig, true)
+			case portalShortcuts + ".Deactivated":
+				w.dispatch(sig, false)
+			}
+		}
+	}
+}
+
+// dispatch pulls the shortcut id out of an Activated/Deactivated signal
+// (session_handle, shortcut_id, timestamp, options) and routes it to the
+// matching dispatcher's press/release state machine.
+func (w *WaylandBindings) dispatch(sig *dbus.Signal, pressed bool) {
+	if len(sig.Body) < 2 {
+		return
+	}
+	id, ok := sig.Body[1].(string)
+	if !ok {
+		return
+	}
+
+	w.mu.Lock()
+	d := w.byID[id]
+	w.mu.Unlock()
+	if d == nil {
+		return
+	}
+	if pressed {
+		d.press()
+	} else {
+		d.release()
+	}
+}
@@ -0,0 +1,220 @@
+// Package ipc exposes an optional local control socket for external
+// integrations — editor plugins, i3/sway bar scripts, Rofi launchers, or
+// headless test harnesses that want to watch and drive dictation without
+// grabbing global hotkeys or a display at all. Unlike events.Broadcaster
+// (write-only event fanout) and ui/headless (request/response JSON-RPC,
+// only active in full headless mode), the same connections here carry both
+// an event stream out and a command channel in, and coexist with whatever
+// frontend (tray or TUI) is already running.
+package ipc
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Event is one line written to every connected client.
+type Event struct {
+	Type       string `json:"type"`
+	Value      string `json:"value,omitempty"`
+	Text       string `json:"text,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// StateEvent reports a UI state transition, e.g. "listening" or "idle".
+func StateEvent(value string) Event { return Event{Type: "state", Value: value} }
+
+// PartialEvent reports an interim transcript during streaming recognition.
+func PartialEvent(text string) Event { return Event{Type: "partial", Text: text} }
+
+// FinalEvent reports the completed transcript for a dictation.
+func FinalEvent(text string, durationMs int64) Event {
+	return Event{Type: "final", Text: text, DurationMs: durationMs}
+}
+
+// ErrorEvent reports a non-fatal error encountered during dictation.
+func ErrorEvent(message string) Event { return Event{Type: "error", Message: message} }
+
+// ClipboardEvent reports text that was just put on the system clipboard and
+// pasted — pushed to every subscriber whenever a "set_clipboard" command or
+// a clipboard-paste typing op runs, so a remote session can mirror the
+// clipboard the way a KVM-over-web tool does. The xdotool and wtype/ydotool
+// typers restore the previous clipboard contents right after the paste, so
+// with those backends the clipboard may no longer hold text by then.
+func ClipboardEvent(text string) Event { return Event{Type: "clipboard", Text: text} }
+
+// Command is one line read from a client.
+type Command struct {
+	Cmd   string `json:"cmd"`
+	Value string `json:"value,omitempty"`
+	Text  string `json:"text,omitempty"`
+}
+
+// Handlers are the daemon operations an IPC client can invoke. A nil
+// handler silently ignores the command instead of panicking.
+type Handlers struct {
+	Toggle       func()
+	Start        func()
+	Stop         func()
+	Undo         func()
+	SetLanguage  func(string)
+	Type         func(string) error
+	GetClipboard func() string
+	SetClipboard func(string) error
+}
+
+// Server fans every Emit'd event out to all connected clients and
+// dispatches each client's incoming command lines to Handlers.
+type Server struct {
+	ln net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// SocketPath returns the default socket path under $XDG_RUNTIME_DIR,
+// falling back to os.TempDir() when it isn't set.
+func SocketPath() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "gotalk-dictation.sock")
+}
+
+// Serve starts accepting connections on socketPath, removing any stale
+// socket file left behind by a previous run. Every connection receives
+// every Emit'd event and may send command lines handled by h.
+func Serve(socketPath string, h Handlers) (*Server, error) {
+	os.Remove(socketPath) //nolint:errcheck
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", socketPath, err)
+	}
+	s := &Server{ln: ln, conns: make(map[net.Conn]struct{})}
+	go s.acceptLoop(h)
+	return s, nil
+}
+
+func (s *Server) acceptLoop(h Handlers) {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		go s.handleConn(conn, h)
+	}
+}
+
+func (s *Server) handleConn(conn net.Conn, h Handlers) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close() //nolint:errcheck
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var cmd Command
+		if err := json.Unmarshal(scanner.Bytes(), &cmd); err != nil {
+			continue
+		}
+		s.dispatch(conn, cmd, h)
+	}
+}
+
+func (s *Server) dispatch(conn net.Conn, cmd Command, h Handlers) {
+	switch cmd.Cmd {
+	case "toggle":
+		if h.Toggle != nil {
+			h.Toggle()
+		}
+	case "start":
+		if h.Start != nil {
+			h.Start()
+		}
+	case "stop":
+		if h.Stop != nil {
+			h.Stop()
+		}
+	case "undo":
+		if h.Undo != nil {
+			h.Undo()
+		}
+	case "set_language":
+		if h.SetLanguage != nil {
+			h.SetLanguage(cmd.Value)
+		}
+	case "type":
+		if h.Type != nil {
+			h.Type(cmd.Text) //nolint:errcheck
+		}
+	case "get_clipboard":
+		if h.GetClipboard != nil {
+			writeEvent(conn, ClipboardEvent(h.GetClipboard()))
+		}
+	case "set_clipboard":
+		if h.SetClipboard != nil {
+			if err := h.SetClipboard(cmd.Text); err == nil {
+				s.Emit(ClipboardEvent(cmd.Text))
+			}
+		}
+	}
+}
+
+// writeEvent sends ev to a single client, for replies (e.g. "get_clipboard")
+// that only the requester should see rather than every subscriber.
+func writeEvent(conn net.Conn, ev Event) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	conn.Write(append(data, '\n')) //nolint:errcheck
+}
+
+// Emit sends ev to every connected client. A nil *Server discards it, so
+// callers never need to guard every call site on whether --ipc was passed.
+func (s *Server) Emit(ev Event) {
+	if s == nil {
+		return
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		if _, err := conn.Write(data); err != nil {
+			conn.Close() //nolint:errcheck
+			delete(s.conns, conn)
+		}
+	}
+}
+
+// Close disconnects every client and stops accepting new connections. Safe
+// to call on a nil *Server.
+func (s *Server) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close() //nolint:errcheck
+		delete(s.conns, conn)
+	}
+	s.mu.Unlock()
+	return s.ln.Close()
+}
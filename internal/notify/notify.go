@@ -0,0 +1,64 @@
+// Package notify plays short audio cues and fires desktop notifications for
+// dictation lifecycle events (start, stop, error, undo). The app is meant to
+// be invoked from anywhere via a global hotkey, so a user with their eyes
+// off the popup still gets non-visual confirmation that the hotkey actually
+// fired.
+package notify
+
+import "os/exec"
+
+// Notifier plays a sound and/or sends a desktop notification for each
+// lifecycle event. Either can be disabled independently; a zero-value
+// Notifier is inert.
+type Notifier struct {
+	SoundEnabled  bool
+	NotifyEnabled bool
+
+	// SoundStart, SoundStop, SoundError, and SoundUndo are paths to WAV/OGG
+	// files played via paplay. Blank disables the cue for that event even if
+	// SoundEnabled is true.
+	SoundStart string
+	SoundStop  string
+	SoundError string
+	SoundUndo  string
+}
+
+// Start fires the "recording started" cue.
+func (n *Notifier) Start() { n.fire(func() string { return n.SoundStart }, "Listening…") }
+
+// Stop fires the "recording finished" cue, with the recognized text (if any)
+// as the notification body.
+func (n *Notifier) Stop(text string) {
+	if text == "" {
+		text = "Done"
+	}
+	n.fire(func() string { return n.SoundStop }, text)
+}
+
+// Error fires the "recording failed" cue (also used for timeouts), with msg
+// as the notification body.
+func (n *Notifier) Error(msg string) {
+	n.fire(func() string { return n.SoundError }, "Error: "+msg)
+}
+
+// Undo fires the "last dictation undone" cue.
+func (n *Notifier) Undo() { n.fire(func() string { return n.SoundUndo }, "Undo") }
+
+// fire plays sound (if SoundEnabled and the file resolved by soundFile is
+// non-blank) and sends a desktop notification (if NotifyEnabled) with body.
+// Both are best-effort: a missing paplay/notify-send binary must never block
+// or fail dictation. soundFile is a closure rather than a plain argument so a
+// nil Notifier never has to dereference itself to resolve it.
+func (n *Notifier) fire(soundFile func() string, body string) {
+	if n == nil {
+		return
+	}
+	if n.SoundEnabled {
+		if sound := soundFile(); sound != "" {
+			go exec.Command("paplay", sound).Run() //nolint:errcheck
+		}
+	}
+	if n.NotifyEnabled {
+		go exec.Command("notify-send", "GoTalk Dictation", body).Run() //nolint:errcheck
+	}
+}
@@ -0,0 +1,21 @@
+package notify
+
+import "testing"
+
+func TestNotifierDisabledIsNoop(t *testing.T) {
+	n := &Notifier{SoundStart: "/tmp/start.wav"}
+	// Neither SoundEnabled nor NotifyEnabled is set, so fire must not shell
+	// out to paplay/notify-send.
+	n.Start()
+	n.Stop("hello")
+	n.Error("boom")
+	n.Undo()
+}
+
+func TestNotifierNilReceiverIsNoop(t *testing.T) {
+	var n *Notifier
+	n.Start()
+	n.Stop("hello")
+	n.Error("boom")
+	n.Undo()
+}
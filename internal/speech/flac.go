@@ -1,13 +1,17 @@
 package speech
 
-// Pure Go FLAC verbatim (uncompressed) encoder.
-// Supports: 16-bit signed PCM, 16 kHz sample rate, mono channel.
+// Pure Go FLAC encoder: CONSTANT and FIXED-predictor (orders 0-4) subframes
+// with partitioned Rice-coded residuals, falling back to VERBATIM when
+// nothing compresses better.
+// Supports: 16- or 24-bit signed PCM, mono channel, at 8/16/22.05/32/44.1/48 kHz.
 // Implements only what is needed for the Google Speech API (FLAC subset 0).
 // No external tools required.
 
 import (
 	"bytes"
+	"crypto/md5"
 	"encoding/binary"
+	"fmt"
 )
 
 const (
@@ -16,54 +20,112 @@ const (
 	flacBlockSize  = 4096 // samples per frame (last frame may be smaller)
 )
 
+// flacSupportedSampleRates are the rates the STREAMINFO/frame-header
+// "get rate/size from STREAMINFO" codes (used unconditionally below) are
+// valid for verifying against in a strict decoder.
+var flacSupportedSampleRates = map[int]bool{
+	8000: true, 16000: true, 22050: true, 32000: true, 44100: true, 48000: true,
+}
+
+var flacSupportedBitDepths = map[int]bool{16: true, 24: true}
+
+// EncodeParams configures pcmToFLACNativeParams. Channels must be 1: the
+// encoder writes a single subframe per frame and has no stereo decorrelation.
+type EncodeParams struct {
+	SampleRate    int
+	BitsPerSample int
+	Channels      int
+}
+
+// validate rejects combinations flacEncodeSubframe/flacStreamInfo can't
+// represent, rather than silently mis-encoding the header.
+func (p EncodeParams) validate() error {
+	if !flacSupportedSampleRates[p.SampleRate] {
+		return fmt.Errorf("flac: unsupported sample rate %d", p.SampleRate)
+	}
+	if !flacSupportedBitDepths[p.BitsPerSample] {
+		return fmt.Errorf("flac: unsupported bit depth %d", p.BitsPerSample)
+	}
+	if p.Channels != 1 {
+		return fmt.Errorf("flac: unsupported channel count %d (only mono is implemented)", p.Channels)
+	}
+	return nil
+}
+
 // pcmToFLACNative encodes raw S16LE PCM (16 kHz, mono) to FLAC without any
-// external tools by using the VERBATIM (pass-through) subframe type.
+// external tools, using the current package defaults.
 func pcmToFLACNative(pcm []byte) []byte {
-	nSamples := int64(len(pcm) / 2)
+	out, _ := pcmToFLACNativeParams(pcm, EncodeParams{
+		SampleRate:    flacSampleRate,
+		BitsPerSample: flacBPS,
+		Channels:      1,
+	})
+	return out
+}
+
+// pcmToFLACNativeParams encodes raw little-endian signed PCM to FLAC per
+// params, compressing each frame with flacEncodeSubframe and patching the
+// STREAMINFO MD5 signature once the whole stream has been hashed.
+func pcmToFLACNativeParams(pcm []byte, params EncodeParams) ([]byte, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	bytesPerSample := params.BitsPerSample / 8
+	nSamples := int64(len(pcm) / bytesPerSample)
 
 	var out bytes.Buffer
 	out.WriteString("fLaC")
 
 	// METADATA_BLOCK_HEADER: last-block=1, type=STREAMINFO(0), length=34
 	out.Write([]byte{0x80, 0x00, 0x00, 0x22})
-	si := flacStreamInfo(nSamples)
+	siOff := out.Len()
+	si := flacStreamInfo(params, nSamples)
 	out.Write(si[:])
 
+	digest := md5.New()
 	for frameNum := 0; int64(frameNum)*flacBlockSize < nSamples; frameNum++ {
-		start := frameNum * flacBlockSize * 2 // byte offset in pcm
-		end := start + flacBlockSize*2
+		start := frameNum * flacBlockSize * bytesPerSample
+		end := start + flacBlockSize*bytesPerSample
 		if end > len(pcm) {
 			end = len(pcm)
 		}
-		out.Write(flacEncodeFrame(frameNum, nSamples, pcm[start:end]))
+		frame := pcm[start:end]
+		digest.Write(frame)
+		out.Write(flacEncodeFrame(frameNum, nSamples, frame, params.BitsPerSample))
 	}
-	return out.Bytes()
+
+	result := out.Bytes()
+	copy(result[siOff+18:siOff+34], digest.Sum(nil))
+	return result, nil
 }
 
 // flacStreamInfo returns the 34-byte STREAMINFO block payload.
-func flacStreamInfo(totalSamples int64) [34]byte {
+func flacStreamInfo(params EncodeParams, totalSamples int64) [34]byte {
 	var si [34]byte
 	binary.BigEndian.PutUint16(si[0:], flacBlockSize) // min blocksize
 	binary.BigEndian.PutUint16(si[2:], flacBlockSize) // max blocksize
 	// bytes 4-9: min/max framesize = 0 (unknown)
 
 	// Bytes 10-17 pack: sample_rate(20 bits) | channels-1(3) | bps-1(5) | total_samples(36)
-	sr := uint32(flacSampleRate) // 16000 = 0x3E80
-	bpsM1 := uint32(flacBPS - 1) // 15
+	sr := uint32(params.SampleRate)
+	chM1 := uint32(params.Channels - 1)
+	bpsM1 := uint32(params.BitsPerSample - 1)
 	ts := uint64(totalSamples)
 
-	si[10] = byte(sr >> 12)                                          // sr[19:12]
-	si[11] = byte(sr >> 4)                                           // sr[11:4]
-	si[12] = byte((sr&0xF)<<4) | byte(0<<1) | byte(bpsM1>>4)        // sr[3:0] | ch | bps[4]
-	si[13] = byte(bpsM1&0xF)<<4 | byte(ts>>32)                      // bps[3:0] | ts[35:32]
-	binary.BigEndian.PutUint32(si[14:], uint32(ts))      // ts[31:0]
-	// bytes 18-33: MD5 signature (zeros = not computed, valid per spec)
+	si[10] = byte(sr >> 12)                                     // sr[19:12]
+	si[11] = byte(sr >> 4)                                      // sr[11:4]
+	si[12] = byte((sr&0xF)<<4) | byte(chM1<<1) | byte(bpsM1>>4) // sr[3:0] | ch | bps[4]
+	si[13] = byte(bpsM1&0xF)<<4 | byte(ts>>32)                  // bps[3:0] | ts[35:32]
+	binary.BigEndian.PutUint32(si[14:], uint32(ts))             // ts[31:0]
+	// bytes 18-33: MD5 signature, patched in by pcmToFLACNativeParams once known
 	return si
 }
 
-// flacEncodeFrame encodes one FLAC audio frame using the VERBATIM subframe type.
-func flacEncodeFrame(frameNum int, totalSamples int64, pcm []byte) []byte {
-	nSamples := len(pcm) / 2
+// flacEncodeFrame encodes one FLAC audio frame, picking whichever of
+// CONSTANT, FIXED-predictor+Rice, or VERBATIM subframe encodes it smallest.
+func flacEncodeFrame(frameNum int, totalSamples int64, pcm []byte, bps int) []byte {
+	bytesPerSample := bps / 8
+	nSamples := len(pcm) / bytesPerSample
 	isLastPartial := int64((frameNum+1)*flacBlockSize) > totalSamples && nSamples != flacBlockSize
 
 	// ---- Frame Header ----
@@ -98,23 +160,315 @@ func flacEncodeFrame(frameNum int, totalSamples int64, pcm []byte) []byte {
 	// CRC-8 of header bytes so far
 	hdr.WriteByte(flacCRC8(hdr.Bytes()))
 
-	// ---- Subframe: VERBATIM (type 0b000001) ----
-	var sub bytes.Buffer
-	sub.WriteByte(0x02) // 0 | 000001 | 0 = zero-bit | VERBATIM | no-wasted-bits
-
-	// Raw 16-bit samples, stored MSB-first (big-endian, not the little-endian arecord output)
-	for i := 0; i < len(pcm); i += 2 {
-		sub.WriteByte(pcm[i+1]) // high byte
-		sub.WriteByte(pcm[i])   // low byte
-	}
+	// ---- Subframe ----
+	samples := flacDecodeSamples(pcm, bps)
+	sub := flacEncodeSubframe(samples, bps)
 
 	// ---- Frame Footer: CRC-16 over header+subframe ----
-	frameData := append(hdr.Bytes(), sub.Bytes()...)
+	frameData := append(hdr.Bytes(), sub...)
 	crc16 := flacCRC16(frameData)
 	frameData = append(frameData, byte(crc16>>8), byte(crc16))
 	return frameData
 }
 
+// flacDecodeSamples unpacks little-endian signed PCM into sign-extended
+// int32 samples, at either 16 or 24 bits per sample.
+func flacDecodeSamples(pcm []byte, bps int) []int32 {
+	bytesPerSample := bps / 8
+	n := len(pcm) / bytesPerSample
+	samples := make([]int32, n)
+	switch bps {
+	case 16:
+		for i := 0; i < n; i++ {
+			lo := uint16(pcm[2*i])
+			hi := uint16(pcm[2*i+1])
+			samples[i] = int32(int16(hi<<8 | lo))
+		}
+	case 24:
+		for i := 0; i < n; i++ {
+			b0, b1, b2 := uint32(pcm[3*i]), uint32(pcm[3*i+1]), uint32(pcm[3*i+2])
+			v := b0 | b1<<8 | b2<<16
+			if v&0x800000 != 0 {
+				v |= 0xFF000000 // sign-extend from bit 23
+			}
+			samples[i] = int32(v)
+		}
+	}
+	return samples
+}
+
+// Subframe type field values (6 bits), per the FLAC subframe header.
+const (
+	flacSubframeConstant  = 0b000000
+	flacSubframeVerbatim  = 0b000001
+	flacSubframeFixedBase = 0b001000 // + predictor order (0-4)
+
+	flacMaxFixedOrder     = 4
+	flacMaxPartitionOrder = 6
+	flacMaxRiceParam      = 14 // stay clear of the 4-bit escape code (0b1111)
+)
+
+// flacBitWriter packs bits MSB-first into a byte slice, as FLAC subframes
+// require (everything between the byte-aligned frame header and footer).
+type flacBitWriter struct {
+	buf  []byte
+	cur  byte
+	nbit uint // bits already used in cur, 0..7
+}
+
+// writeBits appends the low n bits of v, most-significant bit first.
+func (bw *flacBitWriter) writeBits(v uint64, n uint) {
+	for n > 0 {
+		free := 8 - bw.nbit
+		take := n
+		if take > free {
+			take = free
+		}
+		chunk := byte((v >> (n - take)) & ((1 << take) - 1))
+		bw.cur |= chunk << (free - take)
+		bw.nbit += take
+		n -= take
+		if bw.nbit == 8 {
+			bw.buf = append(bw.buf, bw.cur)
+			bw.cur = 0
+			bw.nbit = 0
+		}
+	}
+}
+
+// writeUnary writes q zero bits followed by a stop bit — the FLAC Rice
+// quotient encoding.
+func (bw *flacBitWriter) writeUnary(q uint32) {
+	for q >= 32 {
+		bw.writeBits(0, 32)
+		q -= 32
+	}
+	if q > 0 {
+		bw.writeBits(0, uint(q))
+	}
+	bw.writeBits(1, 1)
+}
+
+// pad zero-fills up to the next byte boundary.
+func (bw *flacBitWriter) pad() {
+	if bw.nbit > 0 {
+		bw.buf = append(bw.buf, bw.cur)
+		bw.cur = 0
+		bw.nbit = 0
+	}
+}
+
+func flacWriteSubframeHeader(bw *flacBitWriter, subframeType uint64) {
+	bw.writeBits(0, 1)            // zero bit
+	bw.writeBits(subframeType, 6) // subframe type
+	bw.writeBits(0, 1)            // no wasted bits
+}
+
+// flacZigZag maps a signed residual to an unsigned value so its sign bit
+// becomes the low bit, as Rice coding requires.
+func flacZigZag(e int32) uint32 {
+	return uint32((e << 1) ^ (e >> 31))
+}
+
+// flacFixedResidual computes the FIXED predictor residuals of the given
+// order (0-4): residual[i] corresponds to samples[order+i], predicted from
+// the `order` samples immediately before it.
+func flacFixedResidual(samples []int32, order int) []int32 {
+	n := len(samples)
+	res := make([]int32, n-order)
+	for i := order; i < n; i++ {
+		var p int32
+		switch order {
+		case 0:
+			p = 0
+		case 1:
+			p = samples[i-1]
+		case 2:
+			p = 2*samples[i-1] - samples[i-2]
+		case 3:
+			p = 3*samples[i-1] - 3*samples[i-2] + samples[i-3]
+		case 4:
+			p = 4*samples[i-1] - 6*samples[i-2] + 4*samples[i-3] - samples[i-4]
+		}
+		res[i-order] = samples[i] - p
+	}
+	return res
+}
+
+// flacRicePartitionCost estimates the bits needed to Rice-code residual
+// with parameter m: a unary quotient, a stop bit, and m remainder bits per
+// sample.
+func flacRicePartitionCost(residual []int32, m uint) int {
+	bits := 0
+	for _, e := range residual {
+		u := flacZigZag(e)
+		bits += int(u>>m) + 1 + int(m)
+	}
+	return bits
+}
+
+// flacBestRiceParam picks m = floor(log2(mean(|e|))), the usual FLAC
+// heuristic for the Rice parameter of a partition.
+func flacBestRiceParam(residual []int32) uint {
+	if len(residual) == 0 {
+		return 0
+	}
+	var sum uint64
+	for _, e := range residual {
+		a := int64(e)
+		if a < 0 {
+			a = -a
+		}
+		sum += uint64(a)
+	}
+	mean := sum / uint64(len(residual))
+	var m uint
+	for mean > 0 {
+		mean >>= 1
+		m++
+	}
+	if m > 0 {
+		m-- // the loop above computed the bit length, floor(log2)+1; we want floor
+	}
+	if m > flacMaxRiceParam {
+		m = flacMaxRiceParam
+	}
+	return m
+}
+
+// flacBestPartition tries Rice partition orders 0..flacMaxPartitionOrder
+// (restricted to those that evenly divide the block and leave every
+// partition wider than the predictor order) and returns the cheapest one,
+// its per-partition Rice parameters, and its estimated bit cost — the
+// residual coding method/partition-order fields and per-partition
+// parameters, but not the warmup samples or subframe header.
+func flacBestPartition(blockSize, predictorOrder int, residual []int32) (order int, params []uint, bits int) {
+	bestBits := -1
+	for o := 0; o <= flacMaxPartitionOrder; o++ {
+		parts := 1 << o
+		if blockSize%parts != 0 {
+			continue
+		}
+		partLen := blockSize / parts
+		if partLen <= predictorOrder {
+			continue
+		}
+		params2 := make([]uint, parts)
+		total := 2 + 4 + 4*parts // method(2) + partition order(4) + per-partition Rice params(4 each)
+		idx := 0
+		for p := 0; p < parts; p++ {
+			n := partLen
+			if p == 0 {
+				n -= predictorOrder
+			}
+			part := residual[idx : idx+n]
+			idx += n
+			m := flacBestRiceParam(part)
+			params2[p] = m
+			total += flacRicePartitionCost(part, m)
+		}
+		if bestBits < 0 || total < bestBits {
+			bestBits = total
+			order = o
+			params = params2
+			bits = total
+		}
+	}
+	return order, params, bits
+}
+
+// flacSampleBits returns the low bps bits of v's two's complement
+// representation, as written for warmup/VERBATIM samples.
+func flacSampleBits(v int32, bps int) uint64 {
+	return uint64(uint32(v)) & ((1 << uint(bps)) - 1)
+}
+
+// flacEncodeSubframe encodes one channel's samples (bps bits each) as
+// CONSTANT, the cheapest FIXED-predictor + partitioned-Rice-coded residual,
+// or VERBATIM, whichever is smallest, and returns the byte-aligned result.
+func flacEncodeSubframe(samples []int32, bps int) []byte {
+	bw := &flacBitWriter{}
+	n := len(samples)
+
+	constant := n > 0
+	for _, s := range samples {
+		if s != samples[0] {
+			constant = false
+			break
+		}
+	}
+	if constant {
+		flacWriteSubframeHeader(bw, flacSubframeConstant)
+		bw.writeBits(flacSampleBits(samples[0], bps), uint(bps))
+		bw.pad()
+		return bw.buf
+	}
+
+	type candidate struct {
+		order     int
+		partOrder int
+		params    []uint
+		bits      int
+	}
+	var best *candidate
+	maxOrder := flacMaxFixedOrder
+	if maxOrder >= n {
+		maxOrder = n - 1
+	}
+	for order := 0; order <= maxOrder; order++ {
+		residual := flacFixedResidual(samples, order)
+		partOrder, params, bits := flacBestPartition(n, order, residual)
+		if params == nil {
+			continue
+		}
+		bits += order * bps // warmup samples
+		if best == nil || bits < best.bits {
+			best = &candidate{order: order, partOrder: partOrder, params: params, bits: bits}
+		}
+	}
+
+	verbatimBits := n * bps
+	if best == nil || best.bits >= verbatimBits {
+		flacWriteSubframeHeader(bw, flacSubframeVerbatim)
+		for _, s := range samples {
+			bw.writeBits(flacSampleBits(s, bps), uint(bps))
+		}
+		bw.pad()
+		return bw.buf
+	}
+
+	flacWriteSubframeHeader(bw, uint64(flacSubframeFixedBase|best.order))
+	for i := 0; i < best.order; i++ {
+		bw.writeBits(flacSampleBits(samples[i], bps), uint(bps))
+	}
+
+	residual := flacFixedResidual(samples, best.order)
+	bw.writeBits(0, 2) // residual coding method 0: 4-bit Rice parameters
+	bw.writeBits(uint64(best.partOrder), 4)
+
+	parts := 1 << best.partOrder
+	partLen := n / parts
+	idx := 0
+	for p := 0; p < parts; p++ {
+		cnt := partLen
+		if p == 0 {
+			cnt -= best.order
+		}
+		m := best.params[p]
+		bw.writeBits(uint64(m), 4)
+		for _, e := range residual[idx : idx+cnt] {
+			u := flacZigZag(e)
+			bw.writeUnary(u >> m)
+			if m > 0 {
+				bw.writeBits(uint64(u)&((1<<m)-1), m)
+			}
+		}
+		idx += cnt
+	}
+	bw.pad()
+	return bw.buf
+}
+
 // flacUTF8Int encodes a non-negative integer using FLAC's UTF-8-like variable-length coding.
 func flacUTF8Int(v uint64) []byte {
 	switch {
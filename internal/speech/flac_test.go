@@ -2,6 +2,7 @@ package speech
 
 import (
 	"bytes"
+	"crypto/md5"
 	"encoding/binary"
 	"testing"
 )
@@ -40,9 +41,9 @@ func naiveCRC16(data []byte) uint16 {
 
 func TestFlacUTF8Int(t *testing.T) {
 	tests := []struct {
-		name  string
-		v     uint64
-		want  []byte
+		name string
+		v    uint64
+		want []byte
 	}{
 		// Range 1: v < 0x80
 		{"zero", 0x00, []byte{0x00}},
@@ -116,7 +117,7 @@ func TestFlacCRC16KnownValue(t *testing.T) {
 }
 
 func TestFlacStreamInfo(t *testing.T) {
-	si := flacStreamInfo(1024)
+	si := flacStreamInfo(EncodeParams{SampleRate: flacSampleRate, BitsPerSample: flacBPS, Channels: 1}, 1024)
 
 	// Length must be 34
 	if len(si) != 34 {
@@ -178,35 +179,41 @@ func TestPCMToFLACNativeMagic(t *testing.T) {
 }
 
 func TestPCMToFLACNativeSampleByteSwap(t *testing.T) {
-	// S16LE value 0x1234: bytes [0x34, 0x12].
-	// FLAC VERBATIM stores big-endian: should see [0x12, 0x34] in subframe.
+	// A single-sample block is trivially CONSTANT (every sample equals the
+	// first), so S16LE value 0x1234 (bytes [0x34, 0x12]) should appear
+	// big-endian [0x12, 0x34] after a CONSTANT subframe header (0x00).
 	pcm := []byte{0x34, 0x12}
 	out := pcmToFLACNative(pcm)
 
-	// Subframe type byte 0x02 followed by big-endian sample [0x12, 0x34].
-	pattern := []byte{0x02, 0x12, 0x34}
+	pattern := []byte{0x00, 0x12, 0x34}
 	if !bytes.Contains(out, pattern) {
-		t.Errorf("output does not contain big-endian subframe pattern %x\nfull output: %x", pattern, out)
+		t.Errorf("output does not contain big-endian CONSTANT subframe pattern %x\nfull output: %x", pattern, out)
 	}
 }
 
-func TestPCMToFLACNativeSubframeTypeByte(t *testing.T) {
-	// VERBATIM subframe header: high bit=0, type=0b000001, wasted_bits=0 → 0b00000010 = 0x02.
+func TestPCMToFLACNativeConstantSubframeTypeByte(t *testing.T) {
+	// CONSTANT subframe header: high bit=0, type=0b000000, wasted_bits=0 → 0x00.
+	// All-zero PCM is constant, so the encoder must pick CONSTANT over VERBATIM.
+	// The subframe follows the 42-byte stream header and the 8-byte partial
+	// frame header (sync(2) + codes(2) + frame number(1) + blocksize-1(2) + CRC8(1)).
 	pcm := make([]byte, 4)
 	out := pcmToFLACNative(pcm)
-	// 0x02 must appear somewhere after the header/streaminfo (42 bytes).
-	if len(out) <= 42 {
+	if len(out) <= 50 {
 		t.Fatalf("output too short: %d", len(out))
 	}
-	found := false
-	for _, b := range out[42:] {
-		if b == 0x02 {
-			found = true
-			break
-		}
+	if out[50] != 0x00 {
+		t.Errorf("first subframe header byte = 0x%02X, want 0x00 (CONSTANT)", out[50])
 	}
-	if !found {
-		t.Error("subframe type byte 0x02 not found in frame data")
+}
+
+func TestPCMToFLACNativeVerbatimFallbackForNoise(t *testing.T) {
+	// Samples that swing between extremes defeat both the constant check and
+	// FIXED-predictor compression, so the encoder must fall back to VERBATIM
+	// (type 0b000001, header byte 0x02).
+	samples := []int32{32767, -32768, 32767, -32768, 32767, -32768, 32767, -32768}
+	sub := flacEncodeSubframe(samples, flacBPS)
+	if sub[0] != 0x02 {
+		t.Errorf("subframe header byte = 0x%02X, want 0x02 (VERBATIM)", sub[0])
 	}
 }
 
@@ -263,12 +270,13 @@ func TestPCMToFLACNativePartialLastFrame(t *testing.T) {
 	out := pcmToFLACNative(pcm)
 
 	// Find second frame: first frame is at byte 42.
-	// First frame size (non-partial, frameNum=0):
+	// The first frame's 4096 samples are all zero, so the encoder picks a
+	// CONSTANT subframe rather than VERBATIM:
 	// hdr = [0xFF,0xF8, 0xC0, 0x00, flacUTF8Int(0)=0x00, CRC8] = 6 bytes
-	// sub = 1 + flacBlockSize*2 bytes
+	// sub = 1 (header) + 2 (16-bit constant value) = 3 bytes
 	// CRC16 = 2 bytes
-	// Total = 6 + 1 + flacBlockSize*2 + 2 = flacBlockSize*2 + 9 bytes
-	frame1Size := flacBlockSize*2 + 9
+	// Total = 6 + 3 + 2 = 11 bytes
+	frame1Size := 11
 	frame2Start := 42 + frame1Size
 	if len(out) < frame2Start+3 {
 		t.Fatalf("output too short for second frame: %d", len(out))
@@ -285,6 +293,105 @@ func TestPCMToFLACNativePartialLastFrame(t *testing.T) {
 	}
 }
 
+func TestFlacBitWriterPacksMSBFirst(t *testing.T) {
+	bw := &flacBitWriter{}
+	bw.writeBits(0b101, 3)
+	bw.writeBits(0b1, 1)
+	bw.writeBits(0b0000, 4)
+	bw.pad()
+	want := []byte{0b10110000}
+	if !bytes.Equal(bw.buf, want) {
+		t.Errorf("buf = %08b, want %08b", bw.buf, want)
+	}
+}
+
+func TestFlacBitWriterSpansBytes(t *testing.T) {
+	bw := &flacBitWriter{}
+	bw.writeBits(0x1234, 16)
+	bw.pad()
+	want := []byte{0x12, 0x34}
+	if !bytes.Equal(bw.buf, want) {
+		t.Errorf("buf = %x, want %x", bw.buf, want)
+	}
+}
+
+func TestFlacBitWriterUnary(t *testing.T) {
+	bw := &flacBitWriter{}
+	bw.writeUnary(3) // 0001
+	bw.writeUnary(0) // 1
+	bw.pad()
+	want := []byte{0b00011000}
+	if !bytes.Equal(bw.buf, want) {
+		t.Errorf("buf = %08b, want %08b", bw.buf, want)
+	}
+}
+
+func TestFlacZigZag(t *testing.T) {
+	tests := []struct {
+		e    int32
+		want uint32
+	}{
+		{0, 0},
+		{-1, 1},
+		{1, 2},
+		{-2, 3},
+		{2, 4},
+	}
+	for _, tc := range tests {
+		if got := flacZigZag(tc.e); got != tc.want {
+			t.Errorf("flacZigZag(%d) = %d, want %d", tc.e, got, tc.want)
+		}
+	}
+}
+
+func TestFlacFixedResidualOrder0(t *testing.T) {
+	samples := []int32{5, -3, 10}
+	res := flacFixedResidual(samples, 0)
+	want := []int32{5, -3, 10}
+	if !equalInt32(res, want) {
+		t.Errorf("order-0 residual = %v, want %v", res, want)
+	}
+}
+
+func TestFlacFixedResidualOrder1Ramp(t *testing.T) {
+	// A perfect ramp has constant first differences.
+	samples := []int32{10, 20, 30, 40, 50}
+	res := flacFixedResidual(samples, 1)
+	want := []int32{10, 10, 10, 10}
+	if !equalInt32(res, want) {
+		t.Errorf("order-1 residual = %v, want %v", res, want)
+	}
+}
+
+func equalInt32(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFlacEncodeSubframeCompressesSmoothSignal(t *testing.T) {
+	// A smooth ramp should compress far below the VERBATIM size (16 bits/sample).
+	samples := make([]int32, flacBlockSize)
+	for i := range samples {
+		samples[i] = int32(i % 100)
+	}
+	sub := flacEncodeSubframe(samples, flacBPS)
+	verbatimBytes := flacBlockSize * 2
+	if len(sub) >= verbatimBytes {
+		t.Errorf("encoded size = %d bytes, want less than VERBATIM size %d", len(sub), verbatimBytes)
+	}
+	// Header type must be FIXED (0b001000-0b001100 << 1 = 0x10-0x18).
+	if sub[0] < 0x10 || sub[0] > 0x18 {
+		t.Errorf("subframe header byte = 0x%02X, want a FIXED-predictor type", sub[0])
+	}
+}
+
 func TestPCMToFLACNativeEmpty(t *testing.T) {
 	// nil/empty input → valid fLaC header + STREAMINFO, no frames, no panic.
 	out := pcmToFLACNative(nil)
@@ -299,3 +406,53 @@ func TestPCMToFLACNativeEmpty(t *testing.T) {
 		t.Errorf("empty PCM output length = %d, want 42", len(out))
 	}
 }
+
+func TestEncodeParamsValidate(t *testing.T) {
+	valid := EncodeParams{SampleRate: 44100, BitsPerSample: 24, Channels: 1}
+	if err := valid.validate(); err != nil {
+		t.Errorf("validate() on %+v = %v, want nil", valid, err)
+	}
+
+	cases := []EncodeParams{
+		{SampleRate: 11025, BitsPerSample: 16, Channels: 1},
+		{SampleRate: 16000, BitsPerSample: 8, Channels: 1},
+		{SampleRate: 16000, BitsPerSample: 16, Channels: 2},
+	}
+	for _, p := range cases {
+		if err := p.validate(); err == nil {
+			t.Errorf("validate() on %+v = nil, want an error", p)
+		}
+	}
+}
+
+func TestPCMToFLACNativeParamsRejectsInvalidParams(t *testing.T) {
+	_, err := pcmToFLACNativeParams([]byte{0, 0}, EncodeParams{SampleRate: 16000, BitsPerSample: 20, Channels: 1})
+	if err == nil {
+		t.Fatal("expected an error for an unsupported bit depth")
+	}
+}
+
+func TestPCMToFLACNativeParamsStreamInfoMD5(t *testing.T) {
+	// Two constant-subframe samples: the MD5 of the raw PCM bytes must land
+	// in STREAMINFO bytes 18-33 (absolute offset 8+18=26 in the full stream).
+	pcm := []byte{0x34, 0x12, 0x34, 0x12}
+	out, err := pcmToFLACNativeParams(pcm, EncodeParams{SampleRate: flacSampleRate, BitsPerSample: flacBPS, Channels: 1})
+	if err != nil {
+		t.Fatalf("pcmToFLACNativeParams: %v", err)
+	}
+	want := md5.Sum(pcm)
+	got := out[26:42]
+	if !bytes.Equal(got, want[:]) {
+		t.Errorf("STREAMINFO MD5 = %x, want %x", got, want)
+	}
+}
+
+func TestFlacDecodeSamples24Bit(t *testing.T) {
+	// 0x7FFFFF (max positive) and 0x800000 (min negative), little-endian.
+	pcm := []byte{0xFF, 0xFF, 0x7F, 0x00, 0x00, 0x80}
+	samples := flacDecodeSamples(pcm, 24)
+	want := []int32{0x7FFFFF, -0x800000}
+	if !equalInt32(samples, want) {
+		t.Errorf("flacDecodeSamples(24-bit) = %v, want %v", samples, want)
+	}
+}
@@ -14,9 +14,17 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
+	"time"
 
 	speechapi "cloud.google.com/go/speech/apiv1"
 	speechpb "cloud.google.com/go/speech/apiv1/speechpb"
+	"cloud.google.com/go/storage"
+
+	"github.com/Alijeyrad/gotalk-dictation/internal/config"
+	"github.com/Alijeyrad/gotalk-dictation/internal/events"
+	"github.com/Alijeyrad/gotalk-dictation/internal/vosk"
+	"github.com/Alijeyrad/gotalk-dictation/internal/whisper"
 )
 
 const (
@@ -26,19 +34,544 @@ const (
 	sampleRate      = 16000
 )
 
-// Recognizer performs speech-to-text using either the unofficial free Google API
-// (default) or the official Google Cloud Speech API (when credentials are present).
+// Recognizer performs speech-to-text using one of four backends: the
+// unofficial free Google API, the official Google Cloud Speech API, or an
+// offline whisper.cpp or Vosk model.
 type Recognizer struct {
 	Language string
+
+	// SilenceChunks and Sensitivity seed the voice-activity detector; see
+	// config.Config for field semantics. Zero means "use the built-in
+	// default". Call SetLiveConfig to change them after construction —
+	// e.g. from config.Watcher — without dropping an in-flight recording.
+	SilenceChunks int
+	Sensitivity   float64
+
+	// VADAlpha, VADHardFloor, and VADReleaseRatio tune bufferWithVAD's
+	// adaptive noise floor. Zero means "use the built-in default":
+	//   - VADAlpha is the EMA weight for each new RMS sample (≈0.05, slow).
+	//   - VADHardFloor is the minimum onset threshold in RMS units, for a
+	//     dead-silent room where noise*Sensitivity would otherwise be ~0.
+	//   - VADReleaseRatio scales Sensitivity down for the *exit* threshold,
+	//     so brief dips don't end the phrase mid-word (hysteresis).
+	VADAlpha        float64
+	VADHardFloor    float64
+	VADReleaseRatio float64
+
+	// Backend selects which engine Recognize uses; one of the
+	// config.Backend* constants. Empty falls back to the legacy behavior of
+	// picking the cloud API only when credentials are present.
+	Backend string
+
+	// WhisperModelPath, WhisperModelSize, and WhisperThreads configure the
+	// whisper-local backend. See config.Config for field semantics.
+	WhisperModelPath string
+	WhisperModelSize string
+	WhisperThreads   int
+
+	// VoskModelPath and VoskModelName configure the vosk-local backend. See
+	// config.Config for field semantics.
+	VoskModelPath string
+	VoskModelName string
+
+	// Vocabulary biases recognition toward custom words/phrases. See
+	// config.Config for field semantics.
+	Vocabulary []config.VocabPhrase
+
+	// EnableAutomaticPunctuation, AlternativeLanguageCodes, Model,
+	// UseEnhanced, ProfanityFilter, and MaxAlternatives configure
+	// BackendGoogleCloud's RecognitionConfig; every other backend ignores
+	// them. See config.Config for field semantics.
+	EnableAutomaticPunctuation bool
+	AlternativeLanguageCodes   []string
+	Model                      string
+	UseEnhanced                bool
+	ProfanityFilter            bool
+	MaxAlternatives            int32
+
+	// EnableWordTimeOffsets and EnableWordConfidence configure
+	// BackendGoogleCloud's RecognitionConfig for RecognizeWords; every other
+	// backend ignores them. See config.Config for field semantics.
+	EnableWordTimeOffsets bool
+	EnableWordConfidence  bool
+
+	// LongForm and GCSBucket configure BackendGoogleCloud's long-form
+	// dictation path; see config.Config for field semantics and
+	// recognizeCloudLongForm/RecognizeFile for how they're used.
+	LongForm  bool
+	GCSBucket string
+
+	// Events, if set, receives lifecycle events (VAD transitions, recognition
+	// requests/results) as they happen. A nil Events is safe to use — every
+	// Emitter method is a no-op on a nil receiver.
+	Events *events.Emitter
+
+	whisperOnce sync.Once
+	whisperRec  *whisper.Recognizer
+	whisperErr  error
+
+	voskOnce sync.Once
+	voskRec  *vosk.Recognizer
+	voskErr  error
+
+	liveMu            sync.RWMutex
+	liveSilenceChunks int
+	liveSensitivity   float64
+}
+
+// LiveConfig returns the VAD's current silence-chunk count and sensitivity
+// multiplier, falling back to SilenceChunks/Sensitivity and then to built-in
+// defaults. Safe for concurrent use.
+func (r *Recognizer) LiveConfig() (silenceChunks int, sensitivity float64) {
+	r.liveMu.RLock()
+	sc, sens := r.liveSilenceChunks, r.liveSensitivity
+	r.liveMu.RUnlock()
+
+	if sc == 0 {
+		sc = r.silenceChunks()
+	}
+	if sens == 0 {
+		sens = r.sensitivity()
+	}
+	return sc, sens
+}
+
+// SetLiveConfig updates the VAD's silence-chunk count and sensitivity
+// multiplier in place. The change is picked up by bufferWithVAD on its next
+// read of LiveConfig — a recording already in progress keeps running with
+// whatever values it already observed, so the mic is never dropped.
+func (r *Recognizer) SetLiveConfig(silenceChunks int, sensitivity float64) {
+	r.liveMu.Lock()
+	r.liveSilenceChunks = silenceChunks
+	r.liveSensitivity = sensitivity
+	r.liveMu.Unlock()
+}
+
+// silenceChunks returns SilenceChunks, falling back to the built-in default.
+func (r *Recognizer) silenceChunks() int {
+	if r.SilenceChunks > 0 {
+		return r.SilenceChunks
+	}
+	return 12
+}
+
+// sensitivity returns Sensitivity, falling back to the built-in default.
+func (r *Recognizer) sensitivity() float64 {
+	if r.Sensitivity > 0 {
+		return r.Sensitivity
+	}
+	return 2.5
+}
+
+// vadAlpha returns VADAlpha, falling back to the built-in default.
+func (r *Recognizer) vadAlpha() float64 {
+	if r.VADAlpha > 0 {
+		return r.VADAlpha
+	}
+	return 0.05
+}
+
+// vadHardFloor returns VADHardFloor, falling back to the built-in default.
+func (r *Recognizer) vadHardFloor() float64 {
+	if r.VADHardFloor > 0 {
+		return r.VADHardFloor
+	}
+	return 150
+}
+
+// vadReleaseRatio returns VADReleaseRatio, falling back to the built-in default.
+func (r *Recognizer) vadReleaseRatio() float64 {
+	if r.VADReleaseRatio > 0 {
+		return r.VADReleaseRatio
+	}
+	return 0.6
+}
+
+// Backend is implemented by the offline engines Recognizer can use
+// (whisper.Recognizer and vosk.Recognizer), so they are interchangeable. The
+// Google free and cloud paths are methods on Recognizer itself and do not
+// implement it.
+type Backend interface {
+	Recognize(ctx context.Context, pcmS16LE []byte, sampleRate int, lang string) (string, error)
 }
 
 // Recognize transcribes audio from audioCh and returns the text.
-// It automatically selects the free or cloud API based on credential availability.
+// It dispatches to the configured Backend, falling back to defaultBackend
+// when Backend is unset.
 func (r *Recognizer) Recognize(ctx context.Context, audioCh <-chan []byte) (string, error) {
+	r.Events.RecordingStart()
+
+	backend := r.Backend
+	if backend == "" {
+		backend = r.defaultBackend()
+	}
+
+	start := time.Now()
+	var text string
+	var err error
+	switch backend {
+	case config.BackendWhisperLocal:
+		text, err = r.recognizeWhisper(ctx, audioCh)
+	case config.BackendVoskLocal:
+		text, err = r.recognizeVosk(ctx, audioCh)
+	case config.BackendGoogleCloud:
+		if r.LongForm {
+			text, err = r.recognizeCloudLongForm(ctx, audioCh)
+		} else {
+			text, err = r.recognizeCloud(ctx, audioCh)
+		}
+	default:
+		text, err = r.recognizeFree(ctx, audioCh)
+	}
+
+	if err != nil {
+		r.Events.Error(err)
+		return text, err
+	}
+	if backend != config.BackendGoogleCloud {
+		text = applyVocabulary(text, r.Vocabulary)
+	}
+	r.Events.RecognitionResult(text, 0, time.Since(start).Milliseconds(), backend)
+	return text, nil
+}
+
+// speechContexts builds the Cloud Speech SpeechContexts for r.Vocabulary, or
+// nil if there's nothing configured. All phrases share one SpeechContext
+// (and therefore one Boost, taken from the first entry), matching the
+// settings window's single boost slider for the whole list.
+func (r *Recognizer) speechContexts() []*speechpb.SpeechContext {
+	if len(r.Vocabulary) == 0 {
+		return nil
+	}
+	phrases := make([]string, len(r.Vocabulary))
+	for i, v := range r.Vocabulary {
+		phrases[i] = v.Phrase
+	}
+	return []*speechpb.SpeechContext{{
+		Phrases: phrases,
+		Boost:   float32(r.Vocabulary[0].Boost),
+	}}
+}
+
+// recognitionConfig builds the Cloud Speech RecognitionConfig shared by
+// recognizeCloud, streamCloud, and recognizeCloudN. maxAlternatives
+// overrides r.MaxAlternatives so RecognizeN can ask for more alternatives
+// than the configured default without mutating r.
+func (r *Recognizer) recognitionConfig(maxAlternatives int32) *speechpb.RecognitionConfig {
+	return &speechpb.RecognitionConfig{
+		Encoding:                   speechpb.RecognitionConfig_LINEAR16,
+		SampleRateHertz:            sampleRate,
+		LanguageCode:               r.Language,
+		AlternativeLanguageCodes:   r.AlternativeLanguageCodes,
+		SpeechContexts:             r.speechContexts(),
+		EnableAutomaticPunctuation: r.EnableAutomaticPunctuation,
+		Model:                      r.Model,
+		UseEnhanced:                r.UseEnhanced,
+		ProfanityFilter:            r.ProfanityFilter,
+		MaxAlternatives:            maxAlternatives,
+		EnableWordTimeOffsets:      r.EnableWordTimeOffsets,
+		EnableWordConfidence:       r.EnableWordConfidence,
+	}
+}
+
+// defaultBackend picks a backend for a Recognizer whose Backend field is
+// unset: Google Cloud if credentials are available, otherwise whichever
+// offline model the caller has configured (Vosk or whisper.cpp, checked in
+// that order), otherwise the free API as a last resort.
+func (r *Recognizer) defaultBackend() string {
 	if HasCloudCredentials() {
-		return r.recognizeCloud(ctx, audioCh)
+		return config.BackendGoogleCloud
+	}
+	if r.VoskModelPath != "" || r.VoskModelName != "" {
+		return config.BackendVoskLocal
 	}
-	return r.recognizeFree(ctx, audioCh)
+	if r.WhisperModelPath != "" || r.WhisperModelSize != "" {
+		return config.BackendWhisperLocal
+	}
+	return config.BackendGoogleFree
+}
+
+// Partial is one update from RecognizeStream: an interim transcript that may
+// still change, or the final transcript for the phrase once IsFinal is true.
+// Stability mirrors the Google Cloud Speech streaming field of the same
+// name: the API's own confidence (0–1) that an interim Text won't change
+// further, for callers that want to throttle how eagerly they act on it.
+type Partial struct {
+	Text      string
+	IsFinal   bool
+	Stability float32
+}
+
+// RecognizeStream transcribes audio from audioCh and reports interim results
+// as they arrive, so a caller can type a phrase incrementally instead of
+// waiting for end-of-phrase. Only BackendGoogleCloud supports this — it's
+// the only backend whose API reports interim results —
+// and the channel is closed once the stream ends, after the final Partial.
+//
+// Unlike Recognize, RecognizeStream doesn't run bufferWithVAD: the cloud
+// streaming endpoint does its own endpointing (SingleUtterance), so audio is
+// forwarded straight from audioCh.
+func (r *Recognizer) RecognizeStream(ctx context.Context, audioCh <-chan []byte) (<-chan Partial, error) {
+	backend := r.Backend
+	if backend == "" {
+		if HasCloudCredentials() {
+			backend = config.BackendGoogleCloud
+		}
+	}
+	if backend != config.BackendGoogleCloud {
+		return nil, fmt.Errorf("streaming recognition requires backend %q, got %q", config.BackendGoogleCloud, backend)
+	}
+
+	r.Events.RecordingStart()
+	partials, err := r.streamCloud(ctx, audioCh)
+	if err != nil {
+		r.Events.Error(err)
+		return nil, err
+	}
+	return partials, nil
+}
+
+// Alternative is one candidate transcript from RecognizeN, paired with the
+// Cloud Speech API's confidence (0–1) that it's correct.
+type Alternative struct {
+	Transcript string
+	Confidence float32
+}
+
+// RecognizeN is Recognize's multi-candidate twin: instead of the single best
+// transcript, it returns up to r.MaxAlternatives candidates ranked by the
+// API's own confidence. Only BackendGoogleCloud reports alternatives, so
+// RecognizeN requires it the same way RecognizeStream does.
+func (r *Recognizer) RecognizeN(ctx context.Context, audioCh <-chan []byte) ([]Alternative, error) {
+	backend := r.Backend
+	if backend == "" {
+		backend = r.defaultBackend()
+	}
+	if backend != config.BackendGoogleCloud {
+		return nil, fmt.Errorf("recognizing alternatives requires backend %q, got %q", config.BackendGoogleCloud, backend)
+	}
+
+	start := time.Now()
+	r.Events.RecordingStart()
+	alternatives, err := r.recognizeCloudN(ctx, audioCh)
+	if err != nil {
+		r.Events.Error(err)
+		return nil, err
+	}
+	var top string
+	var confidence float64
+	if len(alternatives) > 0 {
+		top = alternatives[0].Transcript
+		confidence = float64(alternatives[0].Confidence)
+	}
+	r.Events.RecognitionResult(top, confidence, time.Since(start).Milliseconds(), backend)
+	return alternatives, nil
+}
+
+// Word is one word of a RecognizeWords result, with the Cloud Speech API's
+// per-word timing and confidence.
+type Word struct {
+	Text       string
+	StartMs    int
+	EndMs      int
+	Confidence float32
+}
+
+// Result is RecognizeWords' return value: the flat transcript alongside its
+// per-word breakdown, for callers that need timing or confidence — captions,
+// SRT/WebVTT export, or highlighting low-confidence words.
+type Result struct {
+	Transcript string
+	Words      []Word
+}
+
+// RecognizeWords is Recognize's word-level twin: it returns a Result with
+// per-word start/end offsets and confidence instead of a flat transcript.
+// Only BackendGoogleCloud reports word-level detail, so RecognizeWords
+// requires it the same way RecognizeStream and RecognizeN do.
+func (r *Recognizer) RecognizeWords(ctx context.Context, audioCh <-chan []byte) (Result, error) {
+	backend := r.Backend
+	if backend == "" {
+		backend = r.defaultBackend()
+	}
+	if backend != config.BackendGoogleCloud {
+		return Result{}, fmt.Errorf("recognizing words requires backend %q, got %q", config.BackendGoogleCloud, backend)
+	}
+
+	start := time.Now()
+	r.Events.RecordingStart()
+	result, err := r.recognizeCloudWords(ctx, audioCh)
+	if err != nil {
+		r.Events.Error(err)
+		return result, err
+	}
+	r.Events.RecognitionResult(result.Transcript, 0, time.Since(start).Milliseconds(), backend)
+	return result, nil
+}
+
+// RecognizeFile transcribes a pre-recorded 16kHz mono 16-bit PCM file at
+// path for bulk dictation — a recorded meeting, say — too long to stream
+// live. Unlike Recognize and its siblings, it never reads from an audioCh:
+// the whole file is uploaded to r.GCSBucket and handed to Cloud Speech's
+// LongRunningRecognize, which has no streaming time limit. Only
+// BackendGoogleCloud supports this, and it additionally requires GCSBucket
+// to be configured.
+func (r *Recognizer) RecognizeFile(ctx context.Context, path string) (string, error) {
+	backend := r.Backend
+	if backend == "" {
+		backend = r.defaultBackend()
+	}
+	if backend != config.BackendGoogleCloud {
+		return "", fmt.Errorf("file-based recognition requires backend %q, got %q", config.BackendGoogleCloud, backend)
+	}
+	if r.GCSBucket == "" {
+		return "", fmt.Errorf("file-based recognition requires config.GCSBucket to be set")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	start := time.Now()
+	r.Events.RecordingStart()
+	r.Events.RecognitionRequest(config.BackendGoogleCloud)
+
+	gcsClient, err := storage.NewClient(ctx)
+	if err != nil {
+		return "", fmt.Errorf("creating storage client: %w", err)
+	}
+	defer gcsClient.Close()
+
+	object := fmt.Sprintf("gotalk-dictation/%d-%s", time.Now().UnixNano(), filepath.Base(path))
+	bucket := gcsClient.Bucket(r.GCSBucket)
+	w := bucket.Object(object).NewWriter(ctx)
+	if _, err := w.Write(data); err != nil {
+		w.Close() //nolint:errcheck
+		return "", fmt.Errorf("uploading to gs://%s/%s: %w", r.GCSBucket, object, err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("uploading to gs://%s/%s: %w", r.GCSBucket, object, err)
+	}
+	defer bucket.Object(object).Delete(context.Background()) //nolint:errcheck
+
+	speechClient, err := speechapi.NewClient(ctx)
+	if err != nil {
+		return "", fmt.Errorf("creating speech client: %w", err)
+	}
+	defer speechClient.Close()
+
+	op, err := speechClient.LongRunningRecognize(ctx, &speechpb.LongRunningRecognizeRequest{
+		Config: r.recognitionConfig(r.MaxAlternatives),
+		Audio: &speechpb.RecognitionAudio{
+			AudioSource: &speechpb.RecognitionAudio_Uri{
+				Uri: fmt.Sprintf("gs://%s/%s", r.GCSBucket, object),
+			},
+		},
+	})
+	if err != nil {
+		r.Events.Error(err)
+		return "", fmt.Errorf("starting long-running recognize: %w", err)
+	}
+
+	resp, err := op.Wait(ctx)
+	if err != nil {
+		r.Events.Error(err)
+		return "", fmt.Errorf("waiting for long-running recognize: %w", err)
+	}
+
+	var transcript string
+	for _, result := range resp.Results {
+		if len(result.Alternatives) == 0 {
+			continue
+		}
+		if transcript != "" {
+			transcript += " "
+		}
+		transcript += result.Alternatives[0].Transcript
+	}
+
+	r.Events.RecognitionResult(transcript, 0, time.Since(start).Milliseconds(), config.BackendGoogleCloud)
+	return transcript, nil
+}
+
+// ---- Offline whisper.cpp backend ------------------------------------------
+
+// recognizeWhisper buffers audio using VAD, then transcribes it on-device
+// with whisper.cpp. The model is downloaded on first use if WhisperModelPath
+// is blank.
+func (r *Recognizer) recognizeWhisper(ctx context.Context, audioCh <-chan []byte) (string, error) {
+	pcm, err := r.bufferWithVAD(ctx, audioCh)
+	if len(pcm) == 0 {
+		return "", err // timeout/cancel or no speech
+	}
+	rec, lerr := r.loadWhisper()
+	if lerr != nil {
+		return "", lerr
+	}
+	r.Events.RecognitionRequest(config.BackendWhisperLocal)
+	return rec.Recognize(ctx, pcm, sampleRate, r.Language)
+}
+
+// loadWhisper lazily resolves the model (downloading it if necessary) and
+// constructs the whisper.cpp context. The result is cached for the lifetime
+// of the Recognizer, since loading a model is expensive.
+func (r *Recognizer) loadWhisper() (*whisper.Recognizer, error) {
+	r.whisperOnce.Do(func() {
+		path := r.WhisperModelPath
+		if path == "" {
+			size := r.WhisperModelSize
+			if size == "" {
+				size = "base.en"
+			}
+			path, r.whisperErr = whisper.EnsureModel(size, "")
+			if r.whisperErr != nil {
+				r.whisperErr = fmt.Errorf("resolving whisper model: %w", r.whisperErr)
+				return
+			}
+		}
+		r.whisperRec, r.whisperErr = whisper.New(path, r.WhisperThreads)
+	})
+	return r.whisperRec, r.whisperErr
+}
+
+// ---- Offline Vosk backend --------------------------------------------------
+
+// recognizeVosk buffers audio using VAD, then transcribes it on-device with
+// Vosk. The model is downloaded on first use if VoskModelPath is blank.
+func (r *Recognizer) recognizeVosk(ctx context.Context, audioCh <-chan []byte) (string, error) {
+	pcm, err := r.bufferWithVAD(ctx, audioCh)
+	if len(pcm) == 0 {
+		return "", err // timeout/cancel or no speech
+	}
+	rec, lerr := r.loadVosk()
+	if lerr != nil {
+		return "", lerr
+	}
+	r.Events.RecognitionRequest(config.BackendVoskLocal)
+	return rec.Recognize(ctx, pcm, sampleRate, r.Language)
+}
+
+// loadVosk lazily resolves the model (downloading it if necessary) and
+// constructs the Vosk recognizer. The result is cached for the lifetime of
+// the Recognizer, since loading a model is expensive.
+func (r *Recognizer) loadVosk() (*vosk.Recognizer, error) {
+	r.voskOnce.Do(func() {
+		path := r.VoskModelPath
+		if path == "" {
+			name := r.VoskModelName
+			if name == "" {
+				name = "vosk-model-small-en-us-0.15"
+			}
+			path, r.voskErr = vosk.EnsureModel(name, "")
+			if r.voskErr != nil {
+				r.voskErr = fmt.Errorf("resolving vosk model: %w", r.voskErr)
+				return
+			}
+		}
+		r.voskRec, r.voskErr = vosk.New(path)
+	})
+	return r.voskRec, r.voskErr
 }
 
 // HasCloudCredentials reports whether Google Cloud credentials are available.
@@ -56,19 +589,43 @@ func HasCloudCredentials() bool {
 // ---- Free (unofficial) API -----------------------------------------------
 
 // recognizeFree buffers audio using VAD, then sends it to the unofficial API.
+// The free endpoint accepts raw WAV directly, so the default path needs no
+// external tools or compression at all; pcmToFLAC (ffmpeg) is only reached
+// if both the WAV upload and the pure-Go FLAC encoder fail, e.g. a proxy
+// that rejects one content type but not the other.
 func (r *Recognizer) recognizeFree(ctx context.Context, audioCh <-chan []byte) (string, error) {
-	pcm, err := bufferWithVAD(ctx, audioCh)
+	pcm, err := r.bufferWithVAD(ctx, audioCh)
 	if len(pcm) == 0 {
 		return "", err // timeout/cancel or no speech
 	}
+	r.Events.RecognitionRequest(config.BackendGoogleFree)
+
+	flacContentType := fmt.Sprintf("audio/x-flac; rate=%d", sampleRate)
+
+	text, wavErr := postFreeAPI(ctx, makeWAV(pcm), "audio/wav", r.Language)
+	if wavErr == nil || ctx.Err() != nil {
+		return text, wavErr
+	}
+
+	text, flacErr := postFreeAPI(ctx, pcmToFLACNative(pcm), flacContentType, r.Language)
+	if flacErr == nil || ctx.Err() != nil {
+		return text, flacErr
+	}
+
 	flac, ferr := pcmToFLAC(pcm)
 	if ferr != nil {
-		return "", fmt.Errorf("encoding audio: %w", ferr)
+		return "", fmt.Errorf("wav upload: %v; native flac upload: %v; ffmpeg encode: %w", wavErr, flacErr, ferr)
+	}
+	text, err = postFreeAPI(ctx, flac, flacContentType, r.Language)
+	if err != nil {
+		return "", fmt.Errorf("wav upload: %v; native flac upload: %v; ffmpeg flac upload: %w", wavErr, flacErr, err)
 	}
-	return postFreeAPI(ctx, flac, r.Language)
+	return text, nil
 }
 
-// pcmToFLAC converts raw 16-bit mono 16kHz PCM to FLAC using ffmpeg.
+// pcmToFLAC converts raw 16-bit mono 16kHz PCM to FLAC using ffmpeg. Kept as
+// a fallback for environments where pcmToFLACNative's output is rejected;
+// see recognizeFree.
 func pcmToFLAC(pcm []byte) ([]byte, error) {
 	cmd := exec.Command("ffmpeg",
 		"-hide_banner", "-loglevel", "error",
@@ -85,14 +642,30 @@ func pcmToFLAC(pcm []byte) ([]byte, error) {
 	return out, nil
 }
 
-// bufferWithVAD collects PCM audio, performing energy-based voice activity detection.
-// Returns audio from speech start through end-of-phrase silence.
-func bufferWithVAD(ctx context.Context, audioCh <-chan []byte) ([]byte, error) {
+// bufferWithVAD collects PCM audio, performing energy-based voice activity
+// detection with an adaptive noise floor. Returns audio from speech start
+// through end-of-phrase silence.
+//
+// Unlike a one-shot calibration, the noise floor keeps tracking ambient RMS
+// via an EMA (noise = alpha*rms + (1-alpha)*noise) for as long as we're not
+// in speech, so a fan spinning up mid-session or a headset gain change don't
+// leave the threshold stuck at whatever the room sounded like at startup.
+// Onset and release use asymmetric thresholds (release is the lower of the
+// two) so a brief dip in volume mid-phrase doesn't end it early.
+//
+// silenceEndChunks and sensitivity are read live from r via LiveConfig(), so
+// a config reload takes effect on the very next VAD cycle without dropping
+// the mic. VADAlpha/VADHardFloor/VADReleaseRatio are not live-reloadable.
+func (r *Recognizer) bufferWithVAD(ctx context.Context, audioCh <-chan []byte) ([]byte, error) {
+	silenceEndChunks, sensitivity := r.LiveConfig()
+	alpha := r.vadAlpha()
+	hardFloor := r.vadHardFloor()
+	releaseRatio := r.vadReleaseRatio()
+	floorNoise := hardFloor / sensitivity // invariant: noise never drops below this
+
 	const (
-		calibChunks        = 8  // ~0.5 s of ambient calibration
-		speechThresholdMul = 2.5
-		minSpeechChunks    = 2  // debounce: N consecutive loud chunks = speech
-		silenceEndChunks   = 8  // ~1 s of quiet after speech ends phrase
+		calibChunks     = 4 // ~0.25 s of ambient calibration
+		minSpeechChunks = 2 // debounce: N consecutive loud chunks = speech
 	)
 
 	type state int
@@ -103,14 +676,14 @@ func bufferWithVAD(ctx context.Context, audioCh <-chan []byte) ([]byte, error) {
 	)
 
 	var (
-		cur            state
-		calibCount     int
-		calibRMSSum    float64
-		threshold      float64
-		speechCount    int
-		silenceCount   int
-		preSpeech      [][]byte // small ring buffer kept before speech starts
-		result         []byte
+		cur          state
+		calibCount   int
+		calibRMSSum  float64
+		noise        float64 // adaptive ambient RMS estimate; frozen during inSpeech
+		speechCount  int
+		silenceCount int
+		preSpeech    [][]byte // small ring buffer kept before speech starts
+		result       []byte
 	)
 
 	for {
@@ -124,16 +697,16 @@ func bufferWithVAD(ctx context.Context, audioCh <-chan []byte) ([]byte, error) {
 			}
 
 			rms := calcRMS(chunk)
+			r.Events.Chunk(int64(len(chunk))*1000/int64(sampleRate*2), rms)
 
 			switch cur {
 			case calibrating:
 				calibCount++
 				calibRMSSum += rms
 				if calibCount >= calibChunks {
-					ambient := calibRMSSum / float64(calibCount)
-					threshold = ambient * speechThresholdMul
-					if threshold < 150 {
-						threshold = 150
+					noise = calibRMSSum / float64(calibCount)
+					if noise < floorNoise {
+						noise = floorNoise
 					}
 					cur = waitingSpeech
 				}
@@ -144,10 +717,26 @@ func bufferWithVAD(ctx context.Context, audioCh <-chan []byte) ([]byte, error) {
 				if len(preSpeech) > 4 {
 					preSpeech = preSpeech[1:]
 				}
+
+				// Only let quiet frames pull the noise floor, so a loud onset
+				// candidate can't contaminate its own threshold.
+				if rms < 1.5*noise {
+					noise = alpha*rms + (1-alpha)*noise
+					if noise < floorNoise {
+						noise = floorNoise
+					}
+				}
+
+				threshold := noise * sensitivity
+				if threshold < hardFloor {
+					threshold = hardFloor
+				}
+
 				if rms > threshold {
 					speechCount++
 					if speechCount >= minSpeechChunks {
 						cur = inSpeech
+						r.Events.VADSpeech()
 						for _, c := range preSpeech {
 							result = append(result, c...)
 						}
@@ -160,9 +749,13 @@ func bufferWithVAD(ctx context.Context, audioCh <-chan []byte) ([]byte, error) {
 
 			case inSpeech:
 				result = append(result, chunk...)
-				if rms <= threshold {
+				// Release threshold is lower than onset's, so a brief dip
+				// mid-word doesn't trip end-of-phrase silence.
+				releaseThreshold := noise * sensitivity * releaseRatio
+				if rms <= releaseThreshold {
 					silenceCount++
 					if silenceCount >= silenceEndChunks {
+						r.Events.VADSilence()
 						return result, nil
 					}
 				} else {
@@ -197,13 +790,13 @@ func makeWAV(pcm []byte) []byte {
 	buf.WriteString("WAVE")
 
 	buf.WriteString("fmt ")
-	binary.Write(&buf, binary.LittleEndian, uint32(16))          //nolint:errcheck // chunk size
-	binary.Write(&buf, binary.LittleEndian, uint16(1))           //nolint:errcheck // PCM
-	binary.Write(&buf, binary.LittleEndian, uint16(1))           //nolint:errcheck // mono
-	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))  //nolint:errcheck
+	binary.Write(&buf, binary.LittleEndian, uint32(16))           //nolint:errcheck // chunk size
+	binary.Write(&buf, binary.LittleEndian, uint16(1))            //nolint:errcheck // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(1))            //nolint:errcheck // mono
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))   //nolint:errcheck
 	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*2)) //nolint:errcheck // byte rate
-	binary.Write(&buf, binary.LittleEndian, uint16(2))           //nolint:errcheck // block align
-	binary.Write(&buf, binary.LittleEndian, uint16(16))          //nolint:errcheck // bits per sample
+	binary.Write(&buf, binary.LittleEndian, uint16(2))            //nolint:errcheck // block align
+	binary.Write(&buf, binary.LittleEndian, uint16(16))           //nolint:errcheck // bits per sample
 
 	buf.WriteString("data")
 	binary.Write(&buf, binary.LittleEndian, size) //nolint:errcheck
@@ -222,8 +815,10 @@ type freeAPIResponse struct {
 	} `json:"result"`
 }
 
-// postFreeAPI sends FLAC audio to the unofficial Google Speech API and returns the transcript.
-func postFreeAPI(ctx context.Context, wavData []byte, language string) (string, error) {
+// postFreeAPI sends audio to the unofficial Google Speech API and returns the
+// transcript. contentType must match how audioData is encoded, e.g.
+// "audio/wav" for makeWAV output or "audio/x-flac; rate=16000" for FLAC.
+func postFreeAPI(ctx context.Context, audioData []byte, contentType, language string) (string, error) {
 	key := os.Getenv("GOOGLE_API_KEY")
 	if key == "" {
 		key = defaultFreeKey
@@ -236,11 +831,11 @@ func postFreeAPI(ctx context.Context, wavData []byte, language string) (string,
 	q.Set("key", key)
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(wavData))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(audioData))
 	if err != nil {
 		return "", fmt.Errorf("building request: %w", err)
 	}
-	req.Header.Set("Content-Type", fmt.Sprintf("audio/x-flac; rate=%d", sampleRate))
+	req.Header.Set("Content-Type", contentType)
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -279,6 +874,8 @@ func postFreeAPI(ctx context.Context, wavData []byte, language string) (string,
 // recognizeCloud streams audio to the Google Cloud Speech-to-Text API.
 // Requires credentials (GOOGLE_APPLICATION_CREDENTIALS or gcloud ADC).
 func (r *Recognizer) recognizeCloud(ctx context.Context, audioCh <-chan []byte) (string, error) {
+	r.Events.RecognitionRequest(config.BackendGoogleCloud)
+
 	client, err := speechapi.NewClient(ctx)
 	if err != nil {
 		return "", fmt.Errorf("creating speech client: %w", err)
@@ -293,11 +890,7 @@ func (r *Recognizer) recognizeCloud(ctx context.Context, audioCh <-chan []byte)
 	if err := stream.Send(&speechpb.StreamingRecognizeRequest{
 		StreamingRequest: &speechpb.StreamingRecognizeRequest_StreamingConfig{
 			StreamingConfig: &speechpb.StreamingRecognitionConfig{
-				Config: &speechpb.RecognitionConfig{
-					Encoding:        speechpb.RecognitionConfig_LINEAR16,
-					SampleRateHertz: sampleRate,
-					LanguageCode:    r.Language,
-				},
+				Config:          r.recognitionConfig(r.MaxAlternatives),
 				SingleUtterance: true,
 				InterimResults:  false,
 			},
@@ -348,3 +941,411 @@ func (r *Recognizer) recognizeCloud(ctx context.Context, audioCh <-chan []byte)
 
 	return finalText, nil
 }
+
+// recognizeCloudN is recognizeCloud's multi-candidate twin: it requests
+// r.MaxAlternatives (or 1, if unset) and returns every alternative of the
+// first final result instead of concatenating just the top transcript.
+func (r *Recognizer) recognizeCloudN(ctx context.Context, audioCh <-chan []byte) ([]Alternative, error) {
+	r.Events.RecognitionRequest(config.BackendGoogleCloud)
+
+	client, err := speechapi.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating speech client: %w", err)
+	}
+	defer client.Close()
+
+	stream, err := client.StreamingRecognize(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating stream: %w", err)
+	}
+
+	maxAlternatives := r.MaxAlternatives
+	if maxAlternatives < 1 {
+		maxAlternatives = 1
+	}
+	if err := stream.Send(&speechpb.StreamingRecognizeRequest{
+		StreamingRequest: &speechpb.StreamingRecognizeRequest_StreamingConfig{
+			StreamingConfig: &speechpb.StreamingRecognitionConfig{
+				Config:          r.recognitionConfig(maxAlternatives),
+				SingleUtterance: true,
+				InterimResults:  false,
+			},
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("sending config: %w", err)
+	}
+
+	go func() {
+		defer stream.CloseSend()
+		for {
+			select {
+			case chunk, ok := <-audioCh:
+				if !ok {
+					return
+				}
+				if err := stream.Send(&speechpb.StreamingRecognizeRequest{
+					StreamingRequest: &speechpb.StreamingRecognizeRequest_AudioContent{
+						AudioContent: chunk,
+					},
+				}); err != nil {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var alternatives []Alternative
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return alternatives, ctx.Err()
+			}
+			return alternatives, fmt.Errorf("receiving: %w", err)
+		}
+		for _, result := range resp.Results {
+			if !result.IsFinal || len(alternatives) > 0 {
+				continue
+			}
+			for _, alt := range result.Alternatives {
+				alternatives = append(alternatives, Alternative{
+					Transcript: alt.Transcript,
+					Confidence: alt.Confidence,
+				})
+			}
+		}
+	}
+
+	return alternatives, nil
+}
+
+// recognizeCloudWords is recognizeCloud's word-level twin: it sets
+// EnableWordTimeOffsets/EnableWordConfidence via recognitionConfig and
+// collects every final result's top alternative into a single Result,
+// concatenating transcripts and words across results the way recognizeCloud
+// concatenates finalText.
+func (r *Recognizer) recognizeCloudWords(ctx context.Context, audioCh <-chan []byte) (Result, error) {
+	r.Events.RecognitionRequest(config.BackendGoogleCloud)
+
+	client, err := speechapi.NewClient(ctx)
+	if err != nil {
+		return Result{}, fmt.Errorf("creating speech client: %w", err)
+	}
+	defer client.Close()
+
+	stream, err := client.StreamingRecognize(ctx)
+	if err != nil {
+		return Result{}, fmt.Errorf("creating stream: %w", err)
+	}
+
+	if err := stream.Send(&speechpb.StreamingRecognizeRequest{
+		StreamingRequest: &speechpb.StreamingRecognizeRequest_StreamingConfig{
+			StreamingConfig: &speechpb.StreamingRecognitionConfig{
+				Config:          r.recognitionConfig(r.MaxAlternatives),
+				SingleUtterance: true,
+				InterimResults:  false,
+			},
+		},
+	}); err != nil {
+		return Result{}, fmt.Errorf("sending config: %w", err)
+	}
+
+	go func() {
+		defer stream.CloseSend()
+		for {
+			select {
+			case chunk, ok := <-audioCh:
+				if !ok {
+					return
+				}
+				if err := stream.Send(&speechpb.StreamingRecognizeRequest{
+					StreamingRequest: &speechpb.StreamingRecognizeRequest_AudioContent{
+						AudioContent: chunk,
+					},
+				}); err != nil {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var result Result
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			return result, fmt.Errorf("receiving: %w", err)
+		}
+		for _, sr := range resp.Results {
+			if !sr.IsFinal || len(sr.Alternatives) == 0 {
+				continue
+			}
+			alt := sr.Alternatives[0]
+			result.Transcript += alt.Transcript
+			for _, w := range alt.Words {
+				result.Words = append(result.Words, Word{
+					Text:       w.Word,
+					StartMs:    int(w.StartTime.AsDuration().Milliseconds()),
+					EndMs:      int(w.EndTime.AsDuration().Milliseconds()),
+					Confidence: w.Confidence,
+				})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// longFormRotateAfter is how long recognizeCloudLongForm lets a single
+// streaming connection run before looking for a silence boundary to rotate
+// to a fresh one. Cloud Speech hard-closes a streaming call at 5 minutes, so
+// this leaves 30s of margin to find that boundary.
+//
+// longFormForceRotateAfter is the last resort: if no quiet chunk has shown up
+// by then (someone reading continuously with no pause quiet enough to trip
+// vadHardFloor), rotate anyway on the next chunk rather than let the server
+// force-close the stream and turn into the exact cutoff error this mode
+// exists to avoid.
+const (
+	longFormRotateAfter      = 4*time.Minute + 30*time.Second
+	longFormForceRotateAfter = 4*time.Minute + 55*time.Second
+)
+
+// recognizeCloudLongForm is recognizeCloud's unbounded-length twin: instead
+// of one StreamingRecognize call with SingleUtterance, it keeps a
+// continuous (SingleUtterance: false) stream open across many phrases and
+// concatenates each one's final transcript, the same way recognizeCloud's
+// finalText would if SingleUtterance didn't cut it off after the first.
+//
+// Cloud Speech closes any streaming call outright after ~5 minutes, so once
+// longFormRotateAfter has elapsed, recognizeCloudLongForm watches incoming
+// chunks for one at or below the VAD's hard silence floor (vadHardFloor) —
+// a cheap stand-in for bufferWithVAD's full adaptive state machine, which is
+// built around buffering one utterance rather than a live relay — and uses
+// that quiet moment to close the old stream, drain its remaining results,
+// and open a replacement before resuming. longFormForceRotateAfter rotates
+// unconditionally if no such quiet moment arrives in time.
+func (r *Recognizer) recognizeCloudLongForm(ctx context.Context, audioCh <-chan []byte) (string, error) {
+	r.Events.RecognitionRequest(config.BackendGoogleCloud)
+
+	client, err := speechapi.NewClient(ctx)
+	if err != nil {
+		return "", fmt.Errorf("creating speech client: %w", err)
+	}
+	defer client.Close()
+
+	openStream := func() (speechpb.Speech_StreamingRecognizeClient, error) {
+		stream, err := client.StreamingRecognize(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("creating stream: %w", err)
+		}
+		if err := stream.Send(&speechpb.StreamingRecognizeRequest{
+			StreamingRequest: &speechpb.StreamingRecognizeRequest_StreamingConfig{
+				StreamingConfig: &speechpb.StreamingRecognitionConfig{
+					Config:          r.recognitionConfig(r.MaxAlternatives),
+					SingleUtterance: false,
+					InterimResults:  false,
+				},
+			},
+		}); err != nil {
+			return nil, fmt.Errorf("sending config: %w", err)
+		}
+		return stream, nil
+	}
+
+	// drain reads stream until CloseSend's EOF, appending every final
+	// result's top transcript onto *transcript.
+	drain := func(stream speechpb.Speech_StreamingRecognizeClient, transcript *string) error {
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return fmt.Errorf("receiving: %w", err)
+			}
+			for _, result := range resp.Results {
+				if !result.IsFinal || len(result.Alternatives) == 0 {
+					continue
+				}
+				if *transcript != "" {
+					*transcript += " "
+				}
+				*transcript += result.Alternatives[0].Transcript
+			}
+		}
+	}
+
+	stream, err := openStream()
+	if err != nil {
+		return "", err
+	}
+
+	var transcript string
+	streamStart := time.Now()
+	recvDone := make(chan error, 1)
+	go func(s speechpb.Speech_StreamingRecognizeClient) { recvDone <- drain(s, &transcript) }(stream)
+
+readLoop:
+	for {
+		select {
+		case <-ctx.Done():
+			stream.CloseSend() //nolint:errcheck
+			<-recvDone
+			return transcript, ctx.Err()
+
+		case chunk, ok := <-audioCh:
+			if !ok {
+				break readLoop
+			}
+
+			elapsed := time.Since(streamStart)
+			dueForSilentRotate := elapsed >= longFormRotateAfter && calcRMS(chunk) <= r.vadHardFloor()
+			dueForForcedRotate := elapsed >= longFormForceRotateAfter
+			if dueForSilentRotate || dueForForcedRotate {
+				stream.CloseSend() //nolint:errcheck
+				if err := <-recvDone; err != nil {
+					return transcript, err
+				}
+				stream, err = openStream()
+				if err != nil {
+					return transcript, err
+				}
+				streamStart = time.Now()
+				recvDone = make(chan error, 1)
+				go func(s speechpb.Speech_StreamingRecognizeClient) { recvDone <- drain(s, &transcript) }(stream)
+				if dueForSilentRotate {
+					continue // the chunk that triggered rotation was silence; nothing lost
+				}
+				// Forced rotation: this chunk may carry mid-word speech, so
+				// still send it on the new stream instead of dropping it.
+			}
+
+			if err := stream.Send(&speechpb.StreamingRecognizeRequest{
+				StreamingRequest: &speechpb.StreamingRecognizeRequest_AudioContent{
+					AudioContent: chunk,
+				},
+			}); err != nil {
+				<-recvDone
+				return transcript, fmt.Errorf("sending audio: %w", err)
+			}
+		}
+	}
+
+	stream.CloseSend() //nolint:errcheck
+	if err := <-recvDone; err != nil {
+		return transcript, err
+	}
+	return transcript, nil
+}
+
+// streamCloud is recognizeCloud's streaming twin: it sets InterimResults on
+// the same StreamingRecognize call and forwards every result — interim and
+// final — to the returned channel instead of buffering only the final text.
+// The channel is closed once the stream ends, whether from SingleUtterance
+// endpointing, ctx cancellation, or an error.
+func (r *Recognizer) streamCloud(ctx context.Context, audioCh <-chan []byte) (<-chan Partial, error) {
+	r.Events.RecognitionRequest(config.BackendGoogleCloud)
+
+	client, err := speechapi.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating speech client: %w", err)
+	}
+
+	stream, err := client.StreamingRecognize(ctx)
+	if err != nil {
+		client.Close() //nolint:errcheck
+		return nil, fmt.Errorf("creating stream: %w", err)
+	}
+
+	if err := stream.Send(&speechpb.StreamingRecognizeRequest{
+		StreamingRequest: &speechpb.StreamingRecognizeRequest_StreamingConfig{
+			StreamingConfig: &speechpb.StreamingRecognitionConfig{
+				Config:          r.recognitionConfig(r.MaxAlternatives),
+				SingleUtterance: true,
+				InterimResults:  true,
+			},
+		},
+	}); err != nil {
+		client.Close() //nolint:errcheck
+		return nil, fmt.Errorf("sending config: %w", err)
+	}
+
+	go func() {
+		defer stream.CloseSend()
+		for {
+			select {
+			case chunk, ok := <-audioCh:
+				if !ok {
+					return
+				}
+				if err := stream.Send(&speechpb.StreamingRecognizeRequest{
+					StreamingRequest: &speechpb.StreamingRecognizeRequest_AudioContent{
+						AudioContent: chunk,
+					},
+				}); err != nil {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	partials := make(chan Partial)
+	go func() {
+		defer close(partials)
+		defer client.Close() //nolint:errcheck
+
+		start := time.Now()
+		var finalText string
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					r.Events.Error(fmt.Errorf("receiving: %w", err))
+				}
+				return
+			}
+			for _, result := range resp.Results {
+				if len(result.Alternatives) == 0 {
+					continue
+				}
+				p := Partial{
+					Text:      result.Alternatives[0].Transcript,
+					IsFinal:   result.IsFinal,
+					Stability: result.Stability,
+				}
+				if p.IsFinal {
+					finalText = p.Text
+				}
+				select {
+				case partials <- p:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+		r.Events.RecognitionResult(finalText, 0, time.Since(start).Milliseconds(), config.BackendGoogleCloud)
+	}()
+
+	return partials, nil
+}
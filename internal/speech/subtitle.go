@@ -0,0 +1,46 @@
+package speech
+
+import "fmt"
+
+// WordsToSRT renders words as a SubRip (.srt) subtitle track, one cue per
+// word. It's meant for "subtitle mode" dictation, where RecognizeWords'
+// per-word timing lets the caller write a caption file directly instead of
+// typing the flat transcript.
+func WordsToSRT(words []Word) string {
+	var out string
+	for i, w := range words {
+		out += fmt.Sprintf("%d\n%s --> %s\n%s\n\n", i+1, srtTimestamp(w.StartMs), srtTimestamp(w.EndMs), w.Text)
+	}
+	return out
+}
+
+// WordsToWebVTT renders words as a WebVTT (.vtt) subtitle track, one cue per
+// word.
+func WordsToWebVTT(words []Word) string {
+	out := "WEBVTT\n\n"
+	for _, w := range words {
+		out += fmt.Sprintf("%s --> %s\n%s\n\n", vttTimestamp(w.StartMs), vttTimestamp(w.EndMs), w.Text)
+	}
+	return out
+}
+
+// srtTimestamp formats ms as SRT's HH:MM:SS,mmm.
+func srtTimestamp(ms int) string {
+	return formatTimestamp(ms, ",")
+}
+
+// vttTimestamp formats ms as WebVTT's HH:MM:SS.mmm.
+func vttTimestamp(ms int) string {
+	return formatTimestamp(ms, ".")
+}
+
+func formatTimestamp(ms int, msSep string) string {
+	if ms < 0 {
+		ms = 0
+	}
+	h := ms / 3600000
+	m := (ms % 3600000) / 60000
+	s := (ms % 60000) / 1000
+	frac := ms % 1000
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, msSep, frac)
+}
@@ -0,0 +1,31 @@
+package speech
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWordsToSRT(t *testing.T) {
+	words := []Word{
+		{Text: "hello", StartMs: 0, EndMs: 500},
+		{Text: "world", StartMs: 500, EndMs: 1230},
+	}
+	srt := WordsToSRT(words)
+	if !strings.Contains(srt, "1\n00:00:00,000 --> 00:00:00,500\nhello\n\n") {
+		t.Errorf("missing first cue in SRT output:\n%s", srt)
+	}
+	if !strings.Contains(srt, "2\n00:00:00,500 --> 00:00:01,230\nworld\n\n") {
+		t.Errorf("missing second cue in SRT output:\n%s", srt)
+	}
+}
+
+func TestWordsToWebVTT(t *testing.T) {
+	words := []Word{{Text: "hi", StartMs: 61000, EndMs: 62500}}
+	vtt := WordsToWebVTT(words)
+	if !strings.HasPrefix(vtt, "WEBVTT\n\n") {
+		t.Errorf("missing WEBVTT header:\n%s", vtt)
+	}
+	if !strings.Contains(vtt, "00:01:01.000 --> 00:01:02.500\nhi\n\n") {
+		t.Errorf("missing cue in WebVTT output:\n%s", vtt)
+	}
+}
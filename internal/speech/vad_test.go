@@ -287,4 +287,113 @@ func TestVADDefaultValues(t *testing.T) {
 	if got := r.sensitivity(); got != 2.5 {
 		t.Errorf("sensitivity() = %f, want 2.5", got)
 	}
+	if got := r.vadAlpha(); got != 0.05 {
+		t.Errorf("vadAlpha() = %f, want 0.05", got)
+	}
+	if got := r.vadHardFloor(); got != 150 {
+		t.Errorf("vadHardFloor() = %f, want 150", got)
+	}
+	if got := r.vadReleaseRatio(); got != 0.6 {
+		t.Errorf("vadReleaseRatio() = %f, want 0.6", got)
+	}
+}
+
+func TestVADNoiseFloorDrift(t *testing.T) {
+	// Ambient noise ramps up gradually during waitingSpeech (e.g. a fan
+	// spinning up); the adaptive noise floor should track it, raising the
+	// onset threshold along with it instead of staying pinned at whatever
+	// the room sounded like at calibration time.
+	r := newTestRecognizer(3)
+
+	const samplesPerChunk = 50
+	quiet := makePCMLoud(5, samplesPerChunk)      // calibration ambient
+	rising := makePCMLoud(85, samplesPerChunk)    // gradually louder ambient
+	midLevel := makePCMLoud(160, samplesPerChunk) // above the ORIGINAL threshold (150), below the drifted one
+	loud := makePCMLoud(1000, samplesPerChunk)
+
+	var chunks [][]byte
+	for i := 0; i < 4; i++ {
+		chunks = append(chunks, quiet)
+	}
+	// Let the noise floor drift upward for a while; each step is within
+	// 1.5x the current noise estimate, so the EMA keeps tracking it.
+	for i := 0; i < 60; i++ {
+		chunks = append(chunks, rising)
+	}
+	// A chunk that would false-trigger against the original, frozen
+	// threshold (150) must NOT trigger against the drifted one.
+	for i := 0; i < 4; i++ {
+		chunks = append(chunks, midLevel)
+	}
+	// Genuine speech still triggers onset.
+	chunks = append(chunks, loud)
+	chunks = append(chunks, loud)
+	for i := 0; i < 5; i++ {
+		chunks = append(chunks, loud)
+	}
+	for i := 0; i < 3; i++ {
+		chunks = append(chunks, quiet)
+	}
+
+	result, err := r.bufferWithVAD(context.Background(), feedChan(chunks))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	chunkBytes := samplesPerChunk * 2
+	// preRoll(4, ending with the 2 onset loud chunks) + 5 more loud + 3
+	// trailing quiet(ending silence).
+	// If midLevel had falsely triggered onset, this would be longer.
+	wantLen := chunkBytes * (4 + 5 + 3)
+	if len(result) != wantLen {
+		t.Errorf("len(result) = %d, want %d (midLevel chunks must not have triggered onset)", len(result), wantLen)
+	}
+}
+
+func TestVADHysteresisRelease(t *testing.T) {
+	// A mid-level dip during inSpeech — quieter than the onset threshold but
+	// still louder than the (lower) release threshold — must not be treated
+	// as end-of-phrase silence. That asymmetry is the whole point of having
+	// separate onset/release thresholds instead of one.
+	r := newTestRecognizer(3)
+
+	const samplesPerChunk = 50
+	quiet := makePCMLoud(5, samplesPerChunk)
+	loud := makePCMLoud(1000, samplesPerChunk)
+	// Onset threshold = max(60*2.5, 150) = 150; release threshold =
+	// 60*2.5*0.6 = 90. A dip at RMS=120 sits between the two.
+	dip := makePCMLoud(120, samplesPerChunk)
+
+	var chunks [][]byte
+	for i := 0; i < 4; i++ {
+		chunks = append(chunks, quiet) // calibration
+	}
+	for i := 0; i < 4; i++ {
+		chunks = append(chunks, quiet) // preRoll
+	}
+	chunks = append(chunks, loud)
+	chunks = append(chunks, loud) // onset
+
+	// A sustained mid-level dip, well beyond SilenceChunks in length: under
+	// a single symmetric threshold this would have ended the phrase, but it
+	// must not, since 120 > the release threshold of 90.
+	for i := 0; i < 6; i++ {
+		chunks = append(chunks, dip)
+	}
+	// Real silence after the dip still ends the phrase.
+	for i := 0; i < 3; i++ {
+		chunks = append(chunks, quiet)
+	}
+
+	result, err := r.bufferWithVAD(context.Background(), feedChan(chunks))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	chunkBytes := samplesPerChunk * 2
+	// preRoll(4, ending with the 2 onset loud chunks) + 6 dip + 3 quiet.
+	wantLen := chunkBytes * (4 + 6 + 3)
+	if len(result) != wantLen {
+		t.Errorf("len(result) = %d, want %d (dip must not have ended the phrase early)", len(result), wantLen)
+	}
 }
@@ -0,0 +1,101 @@
+package speech
+
+import (
+	"strings"
+
+	"github.com/Alijeyrad/gotalk-dictation/internal/config"
+)
+
+// fuzzyThreshold is the maximum normalized edit distance (0–1) for a
+// recognized word to be considered a near-homophone of a vocabulary phrase.
+const fuzzyThreshold = 0.34
+
+// applyVocabulary does fuzzy near-homophone replacement against vocab for
+// backends with no native speech-adaptation support (everything except
+// BackendGoogleCloud, which gets SpeechContexts instead): each word in text
+// is compared against every configured single-word phrase and replaced if
+// it's close enough by normalized Levenshtein distance. Multi-word phrases
+// (e.g. product names) aren't matched this way — there's no segmentation
+// step to find word-run boundaries to compare them against.
+func applyVocabulary(text string, vocab []config.VocabPhrase) string {
+	if len(vocab) == 0 || text == "" {
+		return text
+	}
+	words := strings.Fields(text)
+	for i, w := range words {
+		if best, ok := bestPhraseMatch(w, vocab); ok {
+			words[i] = best
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// bestPhraseMatch returns the closest single-word vocabulary phrase to word,
+// if any are within fuzzyThreshold.
+func bestPhraseMatch(word string, vocab []config.VocabPhrase) (string, bool) {
+	lw := strings.ToLower(word)
+	var best string
+	bestDist := 1.0
+	for _, v := range vocab {
+		phrase := strings.TrimSpace(v.Phrase)
+		if phrase == "" || strings.Contains(phrase, " ") {
+			continue
+		}
+		dist := normalizedLevenshtein(lw, strings.ToLower(phrase))
+		if dist > 0 && dist < bestDist {
+			bestDist = dist
+			best = phrase
+		}
+	}
+	if best != "" && bestDist <= fuzzyThreshold {
+		return best, true
+	}
+	return "", false
+}
+
+// normalizedLevenshtein returns the Levenshtein edit distance between a and
+// b divided by the longer string's length, so one threshold works for both
+// short and long words.
+func normalizedLevenshtein(a, b string) float64 {
+	maxLen := len(a)
+	if len(b) > maxLen {
+		maxLen = len(b)
+	}
+	if maxLen == 0 {
+		return 0
+	}
+	return float64(levenshtein(a, b)) / float64(maxLen)
+}
+
+// levenshtein computes the edit distance between two strings using the
+// standard single-row dynamic-programming recurrence.
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur := make([]int, len(br)+1)
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(cur[j-1]+1, prev[j]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(br)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,135 @@
+package typing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jezek/xgb/xproto"
+)
+
+// MacroEvent is one recorded input event. Keycode is the raw X11 keycode
+// for an ordinary key or button; for an event that is itself a modifier
+// key (Shift/Ctrl/Alt/Super) going up or down, Keycode is left 0 and
+// ModState instead carries the single xproto.ModMaskXxx bit that modifier
+// represents, so playback can re-resolve it against whatever keycode that
+// modifier has on the replaying machine rather than replaying a keycode
+// that may no longer mean the same thing after a keyboard remap.
+type MacroEvent struct {
+	EventType uint8  `json:"event_type"` // xproto.KeyPress, KeyRelease, ButtonPress, or ButtonRelease
+	Keycode   uint8  `json:"keycode,omitempty"`
+	ModState  uint16 `json:"mod_state,omitempty"`
+	DelayMs   int64  `json:"delay_ms"` // time since the previous event
+}
+
+// modifierBit is the ModMask bit MacroEvent.ModState carries for an event
+// that is itself kc going down or up, or 0 if kc isn't one of the
+// modifiers a macro cares about reproducing.
+func modifierBit(x *x11Typer, kc xproto.Keycode) uint16 {
+	switch kc {
+	case x.shiftL:
+		return uint16(xproto.ModMaskShift)
+	case x.ctrlL:
+		return uint16(xproto.ModMaskControl)
+	case x.altL:
+		return uint16(xproto.ModMask1)
+	case x.superL:
+		return uint16(xproto.ModMask4)
+	default:
+		return 0
+	}
+}
+
+// modifierKeycode resolves a ModMaskXxx bit back to x's currently cached
+// keycode for that modifier, the inverse of modifierBit.
+func modifierKeycode(x *x11Typer, bit uint16) xproto.Keycode {
+	switch bit {
+	case uint16(xproto.ModMaskShift):
+		return x.shiftL
+	case uint16(xproto.ModMaskControl):
+		return x.ctrlL
+	case uint16(xproto.ModMask1):
+		return x.altL
+	case uint16(xproto.ModMask4):
+		return x.superL
+	default:
+		return 0
+	}
+}
+
+// Macro is a named, ordered sequence of recorded input events.
+type Macro struct {
+	Name   string       `json:"name"`
+	Events []MacroEvent `json:"events"`
+}
+
+func macroDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "gotalk-dictation", "macros")
+}
+
+func macroPath(name string) string {
+	return filepath.Join(macroDir(), name+".json")
+}
+
+// SaveMacro persists m to ~/.config/gotalk-dictation/macros/<m.Name>.json.
+func SaveMacro(m Macro) error {
+	if err := os.MkdirAll(macroDir(), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(macroPath(m.Name), data, 0644)
+}
+
+// LoadMacro reads the named macro back from disk.
+func LoadMacro(name string) (Macro, error) {
+	data, err := os.ReadFile(macroPath(name))
+	if err != nil {
+		return Macro{}, err
+	}
+	var m Macro
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Macro{}, fmt.Errorf("parsing macro %q: %w", name, err)
+	}
+	return m, nil
+}
+
+// NextMacroName returns an unused "macro-N" name for a recording started
+// from a hotkey rather than named up front. Rename the resulting file to
+// control what "run macro <name>" / "macro:<name>" voice triggers match.
+func NextMacroName() string {
+	entries, err := os.ReadDir(macroDir())
+	if err != nil {
+		return "macro-1"
+	}
+	n := 1
+	for _, e := range entries {
+		var i int
+		if _, err := fmt.Sscanf(strings.TrimSuffix(e.Name(), ".json"), "macro-%d", &i); err == nil && i >= n {
+			n = i + 1
+		}
+	}
+	return fmt.Sprintf("macro-%d", n)
+}
+
+// ParseMacroInvocation reports whether text is a spoken ("run macro foo")
+// or punctuation-style ("macro:foo" token) macro trigger, and if so, the
+// macro name it names.
+func ParseMacroInvocation(text string) (name string, ok bool) {
+	trimmed := strings.TrimSpace(text)
+	const prefix = "run macro "
+	if strings.HasPrefix(strings.ToLower(trimmed), prefix) {
+		return strings.TrimSpace(trimmed[len(prefix):]), true
+	}
+	for _, field := range strings.Fields(trimmed) {
+		if strings.HasPrefix(strings.ToLower(field), "macro:") {
+			return field[len("macro:"):], true
+		}
+	}
+	return "", false
+}
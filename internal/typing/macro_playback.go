@@ -0,0 +1,40 @@
+package typing
+
+import (
+	"time"
+
+	"github.com/jezek/xgb/xproto"
+	"github.com/jezek/xgb/xtest"
+)
+
+// maxMacroDelay caps the inter-event delay macro playback will honor, so a
+// pause recorded while the user stepped away from the keyboard doesn't
+// leave playback hanging.
+const maxMacroDelay = 200 * time.Millisecond
+
+// playMacroEvents replays events through XTest FakeInput on x, preserving
+// each event's recorded delay (capped at maxMacroDelay). Modifier-key
+// events are re-resolved against x's own keyboard mapping rather than the
+// keycode recorded at capture time, so a macro still plays correctly after
+// a keyboard remap.
+func playMacroEvents(x *x11Typer, events []MacroEvent) error {
+	for _, ev := range events {
+		if delay := time.Duration(ev.DelayMs) * time.Millisecond; delay > 0 {
+			if delay > maxMacroDelay {
+				delay = maxMacroDelay
+			}
+			time.Sleep(delay)
+		}
+
+		kc := xproto.Keycode(ev.Keycode)
+		if kc == 0 && ev.ModState != 0 {
+			kc = modifierKeycode(x, ev.ModState)
+		}
+		if kc == 0 {
+			continue
+		}
+		xtest.FakeInput(x.conn, ev.EventType, byte(kc), 0, x.root, 0, 0, 0) //nolint:errcheck
+	}
+	x.conn.Sync()
+	return nil
+}
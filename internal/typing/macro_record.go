@@ -0,0 +1,177 @@
+package typing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/record"
+	"github.com/jezek/xgb/xproto"
+)
+
+// MacroRecorder captures keyboard/mouse activity across every client on the
+// X server via the XRecord extension, so a macro recording isn't limited to
+// events delivered to one window. It needs its own xgb.Conn: the blocking
+// EnableContext call below never returns until the context is disabled, so
+// it would starve every other request on a connection shared with the
+// typer's own XTest traffic.
+type MacroRecorder struct {
+	conn *xgb.Conn
+	ctx  record.Context
+	x11  *x11Typer // for looking up which recorded keycodes are modifiers
+
+	stopCh chan struct{}
+	doneCh chan []MacroEvent
+}
+
+// StartMacroRecording opens a dedicated XRecord connection and begins
+// capturing KeyPress/KeyRelease/ButtonPress/ButtonRelease events from every
+// client (XRecordAllClients). Call Stop to end the capture and get back the
+// recorded events.
+func StartMacroRecording() (*MacroRecorder, error) {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return nil, fmt.Errorf("connecting to X11: %w", err)
+	}
+	if err := record.Init(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("XRecord: %w", err)
+	}
+
+	x11, err := newX11Typer()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("opening keymap connection: %w", err)
+	}
+
+	ctx, err := record.NewContextId(conn)
+	if err != nil {
+		conn.Close()
+		x11.close()
+		return nil, err
+	}
+
+	rng := record.Range{
+		DeviceEvents: record.Range8{
+			First: xproto.KeyPress, // KeyPress..ButtonRelease is contiguous
+			Last:  xproto.ButtonRelease,
+		},
+	}
+	if err := record.CreateContextChecked(
+		conn, ctx, 0, // element header: no extra per-datum header info needed
+		[]record.ClientSpec{record.ClientSpec(record.CSAllClients)},
+		[]record.Range{rng},
+	).Check(); err != nil {
+		conn.Close()
+		x11.close()
+		return nil, fmt.Errorf("creating record context: %w", err)
+	}
+
+	r := &MacroRecorder{
+		conn:   conn,
+		ctx:    ctx,
+		x11:    x11,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan []MacroEvent, 1),
+	}
+	go r.run()
+	return r, nil
+}
+
+// run drives the blocking EnableContext call on its own goroutine, decoding
+// each reply datum into a MacroEvent as it arrives.
+func (r *MacroRecorder) run() {
+	defer close(r.doneCh)
+
+	var events []MacroEvent
+	last := time.Now()
+
+	cookie := record.EnableContext(r.conn, r.ctx)
+	for {
+		reply, err := cookie.Reply()
+		if err != nil || reply == nil {
+			// Stop's DisableContext ends EnableContext's reply stream this
+			// way, so this is the common termination path, not just an
+			// error case — Stop is already blocked reading doneCh and must
+			// still get whatever was captured.
+			r.doneCh <- events
+			return
+		}
+
+		// The reply's data buffer is only valid until the next library call
+		// reuses it; copy it out before doing anything else with the
+		// connection.
+		data := append([]byte(nil), reply.Data...)
+
+		now := time.Now()
+		delay := now.Sub(last)
+		last = now
+
+		for _, ev := range decodeRecordData(data) {
+			ev.DelayMs = delay.Milliseconds()
+			delay = 0 // only the first decoded event in a datum carries the gap
+			events = append(events, ev)
+		}
+
+		select {
+		case <-r.stopCh:
+			r.doneCh <- events
+			return
+		default:
+		}
+	}
+}
+
+// decodeRecordData decodes one XRecord reply datum — a raw X11 protocol
+// event — into the KeyPress/KeyRelease/ButtonPress/ButtonRelease it
+// represents. XRecord delivers events in the server's native wire format
+// (detail byte at offset 1, state at offset 28) rather than as xgb's
+// already-parsed event structs, so this mirrors xproto's own layout instead
+// of going through xproto.KeyPressEvent etc.
+func decodeRecordData(data []byte) []MacroEvent {
+	const wireEventSize = 32
+	var out []MacroEvent
+	for len(data) >= wireEventSize {
+		eventType := data[0] &^ 0x80 // strip the send-event bit
+		switch eventType {
+		case xproto.KeyPress, xproto.KeyRelease, xproto.ButtonPress, xproto.ButtonRelease:
+			detail := data[1]
+			state := uint16(data[28]) | uint16(data[29])<<8
+			out = append(out, MacroEvent{
+				EventType: eventType,
+				Keycode:   detail,
+				ModState:  state & (uint16(xproto.ModMaskShift) | uint16(xproto.ModMaskControl) | uint16(xproto.ModMask1) | uint16(xproto.ModMask4)),
+			})
+		}
+		data = data[wireEventSize:]
+	}
+	return out
+}
+
+// Stop ends the recording, tears down the record context and connection,
+// and returns the captured sequence. Modifier-key events in the sequence
+// have their recorded Keycode cleared in favor of a semantic ModState bit,
+// so PlayMacro re-resolves them against the replaying machine's own
+// keyboard mapping instead of a keycode that may no longer be valid there.
+func (r *MacroRecorder) Stop() []MacroEvent {
+	select {
+	case <-r.stopCh:
+	default:
+		close(r.stopCh)
+	}
+	record.DisableContext(r.conn, r.ctx) //nolint:errcheck
+	record.FreeContext(r.conn, r.ctx)    //nolint:errcheck
+
+	events := <-r.doneCh
+
+	for i, ev := range events {
+		if bit := modifierBit(r.x11, xproto.Keycode(ev.Keycode)); bit != 0 {
+			events[i].Keycode = 0
+			events[i].ModState = bit
+		}
+	}
+
+	r.x11.close()
+	r.conn.Close()
+	return events
+}
@@ -1,8 +1,14 @@
+// Package typing types recognized text into the focused window and manages
+// the system clipboard, over whichever backend fits the session: XdotoolTyper
+// (xdotool/xclip, for Xorg and XWayland), WaylandTyper (wtype/ydotool +
+// wl-clipboard, for native Wayland compositors without the wlr protocols),
+// or WaylandNativeTyper (wlr-virtual-keyboard-unstable-v1 +
+// wlr-data-control-unstable-v1 directly, so the user doesn't need a
+// root/uinput grant for ydotool).
 package typing
 
 import (
-	"bytes"
-	"os/exec"
+	"os"
 	"strings"
 )
 
@@ -25,61 +31,93 @@ var punctuationMap = map[string]string{
 
 const clipboardThreshold = 50 // chars; above this use clipboard paste
 
-type Typer struct {
-	EnablePunctuation bool
-	lastRuneCount     int
+// UsesClipboardPaste reports whether Type(text) will go via
+// SetClipboardAndPaste rather than typing directly, so callers that need
+// to know when the system clipboard is about to change (e.g. ipc's
+// ClipboardEvent broadcast) don't have to duplicate clipboardThreshold.
+func UsesClipboardPaste(text string) bool {
+	return len([]rune(text)) >= clipboardThreshold
 }
 
-func (t *Typer) Type(text string) error {
-	if t.EnablePunctuation {
-		text = processPunctuation(text)
-	}
-	t.lastRuneCount = len([]rune(text))
-	if t.lastRuneCount >= clipboardThreshold {
-		return typeViaClipboard(text)
-	}
-	return exec.Command("xdotool", "type", "--clearmodifiers", "--delay", "0", "--", text).Run()
+// Typer types text into the focused window and manages the system
+// clipboard. NewTyper picks the concrete implementation for the session.
+type Typer interface {
+	// Type sends text into the focused window, applying punctuation
+	// processing first if enabled.
+	Type(text string) error
+	// TypeIncremental updates the focused window's text in place from prev
+	// (what's currently on screen) to next, for streaming recognition's
+	// successively refined partial transcripts.
+	TypeIncremental(prev, next string) error
+	// Undo removes whatever the last Type/TypeIncremental call produced.
+	Undo() error
+	// SendBackspaces sends n BackSpace keystrokes.
+	SendBackspaces(n int) error
+	// GetClipboard returns the current clipboard contents.
+	GetClipboard() string
+	// SetClipboardAndPaste sets the clipboard to text and pastes it into
+	// the focused window.
+	SetClipboardAndPaste(text string) error
+	// PlayMacro replays a previously recorded macro (see MacroRecorder).
+	// Backends that can't synthesize input precisely enough for macro
+	// playback return an error instead.
+	PlayMacro(name string) error
+	// SetEnablePunctuation toggles punctuation processing live, e.g. in
+	// response to a config.Watcher update, without rebuilding the Typer.
+	SetEnablePunctuation(v bool)
 }
 
-// typeViaClipboard saves the current clipboard, writes text to it, pastes,
-// then restores the original clipboard contents.
-func typeViaClipboard(text string) error {
-	// Save current clipboard.
-	saved, _ := exec.Command("xclip", "-selection", "clipboard", "-o").Output()
+// Backend selects which Typer implementation NewTyper builds.
+type Backend string
 
-	// Write new text to clipboard.
-	cmd := exec.Command("xclip", "-selection", "clipboard")
-	cmd.Stdin = bytes.NewReader([]byte(text))
-	if err := cmd.Run(); err != nil {
-		// xclip not available — fall back to xdotool.
-		return exec.Command("xdotool", "type", "--clearmodifiers", "--delay", "0", "--", text).Run()
-	}
+const (
+	BackendAuto          Backend = "auto"
+	BackendX11           Backend = "x11"
+	BackendWayland       Backend = "wayland"
+	BackendWaylandNative Backend = "wayland-native"
+)
 
-	// Paste.
-	if err := exec.Command("xdotool", "key", "--clearmodifiers", "ctrl+v").Run(); err != nil {
-		return err
+// NewTyper builds the requested Typer backend. BackendAuto (and any
+// unrecognized value) probes $WAYLAND_DISPLAY before $DISPLAY: under
+// Wayland it prefers WaylandNativeTyper — no root/uinput grant needed —
+// falling back to WaylandTyper if the compositor doesn't expose the wlr
+// protocols, and otherwise uses XdotoolTyper.
+func NewTyper(backend Backend, enablePunctuation bool) Typer {
+	switch backend {
+	case BackendX11:
+		return &XdotoolTyper{EnablePunctuation: enablePunctuation}
+	case BackendWaylandNative:
+		if t, err := newWaylandNativeTyper(enablePunctuation); err == nil {
+			return t
+		}
+		return &WaylandTyper{EnablePunctuation: enablePunctuation}
+	case BackendWayland:
+		return &WaylandTyper{EnablePunctuation: enablePunctuation}
+	default:
+		if !isWaylandSession() {
+			return &XdotoolTyper{EnablePunctuation: enablePunctuation}
+		}
+		if t, err := newWaylandNativeTyper(enablePunctuation); err == nil {
+			return t
+		}
+		return &WaylandTyper{EnablePunctuation: enablePunctuation}
 	}
+}
 
-	// Restore original clipboard (best-effort).
-	if len(saved) > 0 {
-		restore := exec.Command("xclip", "-selection", "clipboard")
-		restore.Stdin = bytes.NewReader(saved)
-		restore.Run() //nolint:errcheck
+func isWaylandSession() bool {
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		return true
 	}
-	return nil
+	return os.Getenv("DISPLAY") == ""
 }
 
-func (t *Typer) Undo() error {
-	if t.lastRuneCount == 0 {
-		return nil
-	}
-	// Build a BackSpace key sequence.
-	keys := make([]string, t.lastRuneCount)
-	for i := range keys {
-		keys[i] = "BackSpace"
+// commonPrefixLen returns how many leading runes a and b share.
+func commonPrefixLen(a, b []rune) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
 	}
-	t.lastRuneCount = 0
-	return exec.Command("xdotool", append([]string{"key", "--clearmodifiers", "--delay", "0"}, keys...)...).Run()
+	return n
 }
 
 func processPunctuation(text string) string {
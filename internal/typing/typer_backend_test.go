@@ -0,0 +1,48 @@
+package typing
+
+import (
+	"strings"
+	"testing"
+)
+
+// runTyperBackendTests exercises the behavior every Typer backend must get
+// right: short and long (clipboard-paste) text, punctuation processing, and
+// undo. Backend-specific test files (see typer_x11_test.go,
+// typer_wayland_test.go) build a Typer via newTyper and run it through this
+// suite, so the backends stay behaviorally interchangeable instead of
+// drifting apart under separate ad hoc tests.
+func runTyperBackendTests(t *testing.T, newTyper func(enablePunctuation bool) Typer) {
+	t.Run("ShortText", func(t *testing.T) {
+		ty := newTyper(false)
+		if err := ty.Type("hi"); err != nil {
+			t.Fatalf("Type() error: %v", err)
+		}
+	})
+
+	t.Run("LongTextUsesClipboard", func(t *testing.T) {
+		ty := newTyper(false)
+		// 60-char string exceeds clipboardThreshold (50), so this exercises
+		// the SetClipboardAndPaste path rather than direct typing.
+		text := strings.Repeat("a", 60)
+		if err := ty.Type(text); err != nil {
+			t.Fatalf("Type() error for long text: %v", err)
+		}
+	})
+
+	t.Run("WithPunctuation", func(t *testing.T) {
+		ty := newTyper(true)
+		if err := ty.Type("hello period"); err != nil {
+			t.Fatalf("Type() error: %v", err)
+		}
+	})
+
+	t.Run("UndoAfterType", func(t *testing.T) {
+		ty := newTyper(false)
+		if err := ty.Type("abc"); err != nil {
+			t.Fatalf("Type() error: %v", err)
+		}
+		if err := ty.Undo(); err != nil {
+			t.Fatalf("Undo() error: %v", err)
+		}
+	})
+}
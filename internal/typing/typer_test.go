@@ -102,8 +102,49 @@ func TestProcessPunctuationEmpty(t *testing.T) {
 	}
 }
 
+func TestCommonPrefixLen(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"hello", "hello", 5},
+		{"hello wor", "hello world", 9},
+		{"hello world", "hello word", 9},
+		{"", "hello", 0},
+		{"hello", "", 0},
+		{"abc", "xyz", 0},
+	}
+	for _, tc := range tests {
+		got := commonPrefixLen([]rune(tc.a), []rune(tc.b))
+		if got != tc.want {
+			t.Errorf("commonPrefixLen(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestParseMacroInvocation(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantName string
+		wantOK   bool
+	}{
+		{"run macro foo", "foo", true},
+		{"Run Macro Deploy", "Deploy", true},
+		{"macro:standup", "standup", true},
+		{"please macro:deploy now", "deploy", true},
+		{"hello world", "", false},
+		{"", "", false},
+	}
+	for _, tc := range tests {
+		name, ok := ParseMacroInvocation(tc.input)
+		if ok != tc.wantOK || name != tc.wantName {
+			t.Errorf("ParseMacroInvocation(%q) = (%q, %v), want (%q, %v)", tc.input, name, ok, tc.wantName, tc.wantOK)
+		}
+	}
+}
+
 func TestTyperUndoZeroCount(t *testing.T) {
-	ty := &Typer{}
+	ty := &XdotoolTyper{}
 	// lastRuneCount is 0, so Undo() should return nil without invoking xdotool.
 	if err := ty.Undo(); err != nil {
 		t.Errorf("Undo() with zero count returned error: %v", err)
@@ -0,0 +1,32 @@
+//go:build waylandnativetest
+
+package typing
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv("WAYLAND_DISPLAY") == "" {
+		// No Wayland session: skip all native-Wayland tests.
+		os.Exit(0)
+	}
+	ty, err := newWaylandNativeTyper(false)
+	if err != nil {
+		// Compositor doesn't expose zwp_virtual_keyboard_manager_v1: skip.
+		os.Exit(0)
+	}
+	_ = ty
+	os.Exit(m.Run())
+}
+
+func TestWaylandNativeTyperBackend(t *testing.T) {
+	runTyperBackendTests(t, func(enablePunctuation bool) Typer {
+		ty, err := newWaylandNativeTyper(enablePunctuation)
+		if err != nil {
+			t.Fatalf("newWaylandNativeTyper: %v", err)
+		}
+		return ty
+	})
+}
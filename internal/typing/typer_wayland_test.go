@@ -0,0 +1,35 @@
+//go:build waylandtest
+
+package typing
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv("WAYLAND_DISPLAY") == "" {
+		// No Wayland session: skip all Wayland tests.
+		os.Exit(0)
+	}
+	for _, tool := range []string{"wl-copy", "wl-paste"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			// Required tool not found: skip all Wayland tests.
+			os.Exit(0)
+		}
+	}
+	if _, wtypeErr := exec.LookPath("wtype"); wtypeErr != nil {
+		if _, ydotoolErr := exec.LookPath("ydotool"); ydotoolErr != nil {
+			// Neither wtype nor ydotool available: skip all Wayland tests.
+			os.Exit(0)
+		}
+	}
+	os.Exit(m.Run())
+}
+
+func TestWaylandTyperBackend(t *testing.T) {
+	runTyperBackendTests(t, func(enablePunctuation bool) Typer {
+		return &WaylandTyper{EnablePunctuation: enablePunctuation}
+	})
+}
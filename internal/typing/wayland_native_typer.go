@@ -0,0 +1,406 @@
+package typing
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	wl "github.com/rajveermalviya/go-wayland/wayland/client"
+	datacontrol "github.com/rajveermalviya/go-wayland/wayland/unstable/wlr-data-control-unstable-v1"
+	virtualkeyboard "github.com/rajveermalviya/go-wayland/wayland/unstable/wlr-virtual-keyboard-unstable-v1"
+	"golang.org/x/sys/unix"
+)
+
+// clipboardMime is the MIME type WaylandNativeTyper offers/requests for
+// plain-text clipboard contents.
+const clipboardMime = "text/plain;charset=utf-8"
+
+// Evdev keycodes (as in linux/input-event-codes.h) for the fixed keys
+// WaylandNativeTyper presses directly, outside the per-rune keymap.
+const (
+	evdevBackspace = 14
+	evdevLeftCtrl  = 29
+	evdevV         = 47
+)
+
+// waylandNativeMinKeycode is the first XKB keycode WaylandNativeTyper hands
+// out for a newly-seen rune. XKB reserves keycodes below 8.
+const waylandNativeMinKeycode = 9
+
+// WaylandNativeTyper is the sibling of WaylandPopup in the ui package: it
+// drives typing and clipboard directly over wlr-virtual-keyboard-unstable-v1
+// and wlr-data-control-unstable-v1 instead of shelling out to
+// wtype/ydotool/wl-clipboard, so it needs neither the ydotoold daemon nor a
+// root/uinput grant. It only works on compositors that expose both
+// protocols (wlroots-based ones — Sway, etc.); NewTyper falls back to
+// WaylandTyper elsewhere.
+//
+// Synthetic input has no notion of "type this Unicode string" the way
+// XTest/wtype do — a virtual keyboard can only press keycodes defined by
+// whatever XKB keymap it last uploaded. So WaylandNativeTyper keeps its own
+// keymap, growing it (and re-uploading) the first time it sees a rune it
+// hasn't assigned a keycode to yet, mapping each to a "U<codepoint>" XKB
+// Unicode keysym — the same trick wtype itself uses under the hood.
+type WaylandNativeTyper struct {
+	EnablePunctuation bool
+	lastRuneCount     int
+
+	punctMu sync.RWMutex
+
+	display *wl.Display
+	seat    *wl.Seat
+	vk      *virtualkeyboard.ZwpVirtualKeyboardV1
+
+	dcDevice *datacontrol.ZwlrDataControlDeviceV1
+	dcMgr    *datacontrol.ZwlrDataControlManagerV1
+
+	kmMu        sync.Mutex
+	runeKeycode map[rune]uint32
+	nextKeycode uint32
+
+	clipMu    sync.Mutex
+	lastOffer *datacontrol.ZwlrDataControlOfferV1
+}
+
+// newWaylandNativeTyper connects to the compositor and binds wl_seat,
+// zwp_virtual_keyboard_manager_v1, and (best-effort)
+// zwlr_data_control_manager_v1. It fails if the compositor doesn't expose
+// the virtual-keyboard protocol, so NewTyper can fall back to WaylandTyper.
+func newWaylandNativeTyper(enablePunctuation bool) (*WaylandNativeTyper, error) {
+	display, err := wl.Connect("")
+	if err != nil {
+		return nil, fmt.Errorf("wayland: %w", err)
+	}
+
+	t := &WaylandNativeTyper{
+		EnablePunctuation: enablePunctuation,
+		display:           display,
+		runeKeycode:       make(map[rune]uint32),
+		nextKeycode:       waylandNativeMinKeycode,
+	}
+
+	var vkMgr *virtualkeyboard.ZwpVirtualKeyboardManagerV1
+
+	registry, err := display.GetRegistry()
+	if err != nil {
+		display.Context().Close()
+		return nil, fmt.Errorf("wayland: get_registry: %w", err)
+	}
+	registry.SetGlobalHandler(func(ev wl.RegistryGlobalEvent) {
+		switch ev.Interface {
+		case "wl_seat":
+			if t.seat == nil {
+				t.seat, _ = wl.BindSeat(registry, ev.Name, ev.Version)
+			}
+		case "zwp_virtual_keyboard_manager_v1":
+			vkMgr, _ = virtualkeyboard.BindZwpVirtualKeyboardManagerV1(registry, ev.Name, ev.Version)
+		case "zwlr_data_control_manager_v1":
+			t.dcMgr, _ = datacontrol.BindZwlrDataControlManagerV1(registry, ev.Name, ev.Version)
+		}
+	})
+	if err := display.Context().RoundTrip(); err != nil { // let the registry enumerate globals
+		display.Context().Close()
+		return nil, fmt.Errorf("wayland: roundtrip: %w", err)
+	}
+	if t.seat == nil || vkMgr == nil {
+		display.Context().Close()
+		return nil, fmt.Errorf("wayland: compositor does not support zwp_virtual_keyboard_manager_v1")
+	}
+
+	vk, err := vkMgr.CreateVirtualKeyboard(t.seat)
+	if err != nil {
+		display.Context().Close()
+		return nil, fmt.Errorf("wayland: create_virtual_keyboard: %w", err)
+	}
+	t.vk = vk
+
+	if t.dcMgr != nil {
+		if dev, err := t.dcMgr.GetDataDevice(t.seat); err == nil {
+			t.dcDevice = dev
+			t.dcDevice.SetSelectionHandler(func(ev datacontrol.ZwlrDataControlDeviceSelectionEvent) {
+				t.clipMu.Lock()
+				t.lastOffer = ev.Id
+				t.clipMu.Unlock()
+			})
+		}
+	}
+
+	if err := t.uploadKeymap(); err != nil {
+		display.Context().Close()
+		return nil, err
+	}
+
+	go t.eventLoop()
+	return t, nil
+}
+
+// eventLoop dispatches the wl_display connection for as long as it stays
+// open, delivering the DataOffer/Selection callbacks registered above.
+func (t *WaylandNativeTyper) eventLoop() {
+	for {
+		if err := t.display.Context().Dispatch(); err != nil {
+			return
+		}
+	}
+}
+
+func (t *WaylandNativeTyper) SetEnablePunctuation(v bool) {
+	t.punctMu.Lock()
+	t.EnablePunctuation = v
+	t.punctMu.Unlock()
+}
+
+func (t *WaylandNativeTyper) Type(text string) error {
+	t.punctMu.RLock()
+	enable := t.EnablePunctuation
+	t.punctMu.RUnlock()
+	if enable {
+		text = processPunctuation(text)
+	}
+	t.lastRuneCount = len([]rune(text))
+	if t.lastRuneCount >= clipboardThreshold {
+		return t.SetClipboardAndPaste(text)
+	}
+	return t.typeRunes(text)
+}
+
+// TypeIncremental is the same converge-on-the-common-prefix approach as
+// XdotoolTyper.TypeIncremental and WaylandTyper.TypeIncremental, over the
+// virtual keyboard instead of xdotool/wtype.
+func (t *WaylandNativeTyper) TypeIncremental(prev, next string) error {
+	t.punctMu.RLock()
+	enable := t.EnablePunctuation
+	t.punctMu.RUnlock()
+	if enable {
+		// prev must go through the same processing as next did when it was
+		// typed, or commonPrefixLen diffs against text that's not what's
+		// actually on screen.
+		prev = processPunctuation(prev)
+		next = processPunctuation(next)
+	}
+
+	prevRunes := []rune(prev)
+	nextRunes := []rune(next)
+	common := commonPrefixLen(prevRunes, nextRunes)
+
+	if n := len(prevRunes) - common; n > 0 {
+		if err := t.SendBackspaces(n); err != nil {
+			return err
+		}
+	}
+	t.lastRuneCount = len(nextRunes)
+
+	return t.typeRunes(string(nextRunes[common:]))
+}
+
+func (t *WaylandNativeTyper) Undo() error {
+	if t.lastRuneCount == 0 {
+		return nil
+	}
+	n := t.lastRuneCount
+	t.lastRuneCount = 0
+	return t.SendBackspaces(n)
+}
+
+// SendBackspaces sends n BackSpace key events. BackSpace is one of the
+// fixed keys uploadKeymap always reserves, so it needs no per-call keymap
+// lookup.
+func (t *WaylandNativeTyper) SendBackspaces(n int) error {
+	for range n {
+		now := waylandKeyTime()
+		if err := t.vk.Key(now, evdevBackspace, 1); err != nil {
+			return err
+		}
+		if err := t.vk.Key(now, evdevBackspace, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetClipboard reads the current zwlr_data_control selection, if the last
+// Selection event handed us an offer.
+func (t *WaylandNativeTyper) GetClipboard() string {
+	t.clipMu.Lock()
+	offer := t.lastOffer
+	t.clipMu.Unlock()
+	if offer == nil {
+		return ""
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return ""
+	}
+	defer r.Close()
+	if err := offer.Receive(clipboardMime, w.Fd()); err != nil {
+		w.Close()
+		return ""
+	}
+	w.Close()
+	t.display.Context().RoundTrip() //nolint:errcheck — flush the Receive request so the compositor starts writing
+
+	data, _ := io.ReadAll(r)
+	return string(data)
+}
+
+// SetClipboardAndPaste creates a data-control selection offering text, then
+// synthesizes Ctrl+V over the virtual keyboard so the focused app pastes it
+// — data-control sets the clipboard but has no "paste into the focused
+// window" request of its own, same division of labor as
+// WaylandTyper.SetClipboardAndPaste over wl-copy + wtype.
+func (t *WaylandNativeTyper) SetClipboardAndPaste(text string) error {
+	if t.dcMgr == nil || t.dcDevice == nil {
+		return t.typeRunes(text)
+	}
+
+	src, err := t.dcMgr.CreateDataSource()
+	if err != nil {
+		return fmt.Errorf("wayland: create_data_source: %w", err)
+	}
+	if err := src.Offer(clipboardMime); err != nil {
+		return fmt.Errorf("wayland: offer: %w", err)
+	}
+	src.SetSendHandler(func(ev datacontrol.ZwlrDataControlSourceSendEvent) {
+		w := os.NewFile(ev.Fd, "wayland-clipboard-send")
+		defer w.Close()
+		io.WriteString(w, text) //nolint:errcheck
+	})
+	if err := t.dcDevice.SetSelection(src); err != nil {
+		return fmt.Errorf("wayland: set_selection: %w", err)
+	}
+
+	now := waylandKeyTime()
+	t.vk.Key(now, evdevLeftCtrl, 1) //nolint:errcheck
+	t.vk.Key(now, evdevV, 1)        //nolint:errcheck
+	t.vk.Key(now, evdevV, 0)        //nolint:errcheck
+	t.vk.Key(now, evdevLeftCtrl, 0) //nolint:errcheck
+	return nil
+}
+
+// PlayMacro is unsupported on WaylandNativeTyper for the same reason as
+// WaylandTyper: macro capture records raw X11 keycodes via XRecord, which
+// have no meaning to a wlr-virtual-keyboard session.
+func (t *WaylandNativeTyper) PlayMacro(name string) error {
+	return fmt.Errorf("macro playback needs the X11 backend (XRecord/XTest); current backend is wayland-native")
+}
+
+// typeRunes grows the keymap to cover any rune in text it hasn't assigned a
+// keycode to yet, then presses and releases each rune's keycode in order.
+func (t *WaylandNativeTyper) typeRunes(text string) error {
+	if text == "" {
+		return nil
+	}
+	if err := t.ensureKeycodes(text); err != nil {
+		return err
+	}
+
+	t.kmMu.Lock()
+	defer t.kmMu.Unlock()
+	for _, r := range text {
+		kc := t.runeKeycode[r]
+		now := waylandKeyTime()
+		if err := t.vk.Key(now, kc-8, 1); err != nil {
+			return err
+		}
+		if err := t.vk.Key(now, kc-8, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureKeycodes assigns an unused XKB keycode to every rune in text that
+// doesn't have one yet, re-uploading the keymap if it grew.
+func (t *WaylandNativeTyper) ensureKeycodes(text string) error {
+	t.kmMu.Lock()
+	grew := false
+	for _, r := range text {
+		if _, ok := t.runeKeycode[r]; ok {
+			continue
+		}
+		// Skip the keycodes buildKeymapText gives the fixed keys.
+		for t.nextKeycode == evdevBackspace+8 || t.nextKeycode == evdevLeftCtrl+8 || t.nextKeycode == evdevV+8 {
+			t.nextKeycode++
+		}
+		t.runeKeycode[r] = t.nextKeycode
+		t.nextKeycode++
+		grew = true
+	}
+	t.kmMu.Unlock()
+	if !grew {
+		return nil
+	}
+	return t.uploadKeymap()
+}
+
+// uploadKeymap builds a minimal XKB keymap — the fixed BackSpace/Ctrl/V
+// keys plus one key per rune assigned so far, each bound to its "U<hex>"
+// Unicode keysym — and sends it to the compositor over a memfd, per
+// wl_keyboard's keymap-by-shared-memory convention.
+func (t *WaylandNativeTyper) uploadKeymap() error {
+	t.kmMu.Lock()
+	text := t.buildKeymapText()
+	t.kmMu.Unlock()
+
+	fd, err := unix.MemfdCreate("gotalk-dictation-keymap", 0)
+	if err != nil {
+		return fmt.Errorf("wayland: memfd_create: %w", err)
+	}
+	defer unix.Close(fd)
+
+	data := append([]byte(text), 0) // NUL-terminated, as wl_keyboard.keymap requires
+	if err := unix.Ftruncate(fd, int64(len(data))); err != nil {
+		return fmt.Errorf("wayland: ftruncate: %w", err)
+	}
+	if _, err := unix.Write(fd, data); err != nil {
+		return fmt.Errorf("wayland: write keymap: %w", err)
+	}
+
+	const xkbV1 = 1 // WL_KEYBOARD_KEYMAP_FORMAT_XKB_V1
+	return t.vk.Keymap(xkbV1, uintptr(fd), uint32(len(data)))
+}
+
+// buildKeymapText renders the keymap runeKeycode currently describes as
+// XKB keymap source text. Caller holds kmMu.
+func (t *WaylandNativeTyper) buildKeymapText() string {
+	var b strings.Builder
+	b.WriteString("xkb_keymap {\n")
+
+	b.WriteString("  xkb_keycodes \"gotalk\" {\n")
+	b.WriteString("    minimum = 8;\n")
+	b.WriteString("    maximum = 255;\n")
+	fmt.Fprintf(&b, "    <BKSP> = %d;\n", evdevBackspace+8)
+	fmt.Fprintf(&b, "    <LCTL> = %d;\n", evdevLeftCtrl+8)
+	fmt.Fprintf(&b, "    <AE0V> = %d;\n", evdevV+8)
+	for r, kc := range t.runeKeycode {
+		fmt.Fprintf(&b, "    <R%03d> = %d; // U+%04X\n", kc, kc, r)
+	}
+	b.WriteString("  };\n")
+
+	b.WriteString("  xkb_types { include \"complete\" };\n")
+	b.WriteString("  xkb_compat { include \"complete\" };\n")
+
+	b.WriteString("  xkb_symbols \"gotalk\" {\n")
+	b.WriteString("    key <BKSP> { [ BackSpace ] };\n")
+	b.WriteString("    key <LCTL> { [ Control_L ] };\n")
+	b.WriteString("    key <AE0V> { [ v, V ] };\n")
+	for r, kc := range t.runeKeycode {
+		fmt.Fprintf(&b, "    key <R%03d> { [ U%04X ] };\n", kc, r)
+	}
+	b.WriteString("  };\n")
+
+	b.WriteString("};\n")
+	return b.String()
+}
+
+// waylandKeyTime returns the event timestamp wl_keyboard/virtual-keyboard
+// requests expect: milliseconds since an arbitrary epoch, monotonically
+// increasing. The compositor only uses it for relative ordering, so
+// wall-clock milliseconds are fine.
+func waylandKeyTime() uint32 {
+	return uint32(time.Now().UnixMilli())
+}
@@ -0,0 +1,163 @@
+package typing
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// WaylandTyper types and manages the clipboard via wtype (preferred) or
+// ydotool (fallback — needs the ydotoold daemon and an input-group/uinput
+// grant), and wl-clipboard's wl-copy/wl-paste. For compositors exposing
+// wlr-virtual-keyboard-unstable-v1 and wlr-data-control-unstable-v1, prefer
+// WaylandNativeTyper instead, which needs neither daemon nor uinput.
+type WaylandTyper struct {
+	EnablePunctuation bool
+	lastRuneCount     int
+
+	punctMu sync.RWMutex
+}
+
+func (t *WaylandTyper) SetEnablePunctuation(v bool) {
+	t.punctMu.Lock()
+	t.EnablePunctuation = v
+	t.punctMu.Unlock()
+}
+
+func (t *WaylandTyper) Type(text string) error {
+	t.punctMu.RLock()
+	enable := t.EnablePunctuation
+	t.punctMu.RUnlock()
+	if enable {
+		text = processPunctuation(text)
+	}
+	t.lastRuneCount = len([]rune(text))
+	if t.lastRuneCount >= clipboardThreshold {
+		return t.SetClipboardAndPaste(text)
+	}
+	return typeWayland(text)
+}
+
+// TypeIncremental updates the target app's text in place from prev to next
+// the same way XdotoolTyper.TypeIncremental does, over wtype/ydotool
+// instead of xdotool.
+func (t *WaylandTyper) TypeIncremental(prev, next string) error {
+	t.punctMu.RLock()
+	enable := t.EnablePunctuation
+	t.punctMu.RUnlock()
+	if enable {
+		// prev must go through the same processing as next did when it was
+		// typed, or commonPrefixLen diffs against text that's not what's
+		// actually on screen.
+		prev = processPunctuation(prev)
+		next = processPunctuation(next)
+	}
+
+	prevRunes := []rune(prev)
+	nextRunes := []rune(next)
+	common := commonPrefixLen(prevRunes, nextRunes)
+
+	if n := len(prevRunes) - common; n > 0 {
+		if err := t.SendBackspaces(n); err != nil {
+			return err
+		}
+	}
+	t.lastRuneCount = len(nextRunes)
+
+	suffix := string(nextRunes[common:])
+	if suffix == "" {
+		return nil
+	}
+	return typeWayland(suffix)
+}
+
+func (t *WaylandTyper) Undo() error {
+	if t.lastRuneCount == 0 {
+		return nil
+	}
+	n := t.lastRuneCount
+	t.lastRuneCount = 0
+	return t.SendBackspaces(n)
+}
+
+func (t *WaylandTyper) SendBackspaces(n int) error {
+	return backspaceWayland(n)
+}
+
+// GetClipboard returns the current wl-clipboard contents.
+func (t *WaylandTyper) GetClipboard() string {
+	out, err := exec.Command("wl-paste", "--no-newline").Output()
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// SetClipboardAndPaste saves the current clipboard, writes text to it,
+// pastes, then restores the original clipboard contents.
+func (t *WaylandTyper) SetClipboardAndPaste(text string) error {
+	saved, _ := exec.Command("wl-paste", "--no-newline").Output()
+
+	cmd := exec.Command("wl-copy")
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		// wl-copy not available — fall back to a direct type.
+		return typeWayland(text)
+	}
+
+	if err := pasteWayland(); err != nil {
+		return err
+	}
+
+	if len(saved) > 0 {
+		restore := exec.Command("wl-copy")
+		restore.Stdin = bytes.NewReader(saved)
+		restore.Run() //nolint:errcheck
+	}
+	return nil
+}
+
+// PlayMacro is unsupported on WaylandTyper: macro recording captures raw
+// X11 keycodes via XRecord, and wtype/ydotool have no equivalent way to
+// replay them precisely.
+func (t *WaylandTyper) PlayMacro(name string) error {
+	return fmt.Errorf("macro playback needs the X11 backend (XRecord/XTest); current backend is Wayland")
+}
+
+// typeWayland sends text via wtype, falling back to ydotool if wtype isn't
+// installed.
+func typeWayland(text string) error {
+	if _, err := exec.LookPath("wtype"); err == nil {
+		return exec.Command("wtype", "--", text).Run()
+	}
+	return exec.Command("ydotool", "type", "--", text).Run()
+}
+
+// backspaceWayland sends n BackSpace keystrokes via wtype, falling back to
+// ydotool's raw evdev keycodes (14 = KEY_BACKSPACE) if wtype isn't
+// installed.
+func backspaceWayland(n int) error {
+	if _, err := exec.LookPath("wtype"); err == nil {
+		args := make([]string, 0, n*2)
+		for range n {
+			args = append(args, "-k", "BackSpace")
+		}
+		return exec.Command("wtype", args...).Run()
+	}
+	args := make([]string, 0, n*2)
+	for range n {
+		args = append(args, "key", "14:1", "14:0")
+	}
+	return exec.Command("ydotool", args...).Run()
+}
+
+// pasteWayland sends Ctrl+V via wtype, falling back to ydotool's raw evdev
+// keycodes (29 = KEY_LEFTCTRL, 47 = KEY_V) if wtype isn't installed.
+func pasteWayland() error {
+	if _, err := exec.LookPath("wtype"); err == nil {
+		return exec.Command("wtype", "-M", "ctrl", "-k", "v", "-m", "ctrl").Run()
+	}
+	return exec.Command("ydotool", "key", "29:1", "47:1", "47:0", "29:0").Run()
+}
@@ -24,6 +24,8 @@ type x11Typer struct {
 	// Cached keycodes
 	shiftL    xproto.Keycode
 	ctrlL     xproto.Keycode
+	altL      xproto.Keycode
+	superL    xproto.Keycode
 	backspace xproto.Keycode
 	vKey      xproto.Keycode
 
@@ -72,6 +74,8 @@ func newX11Typer() (*x11Typer, error) {
 
 	x.shiftL = x.findKeycode(0xFFE1)    // Shift_L
 	x.ctrlL = x.findKeycode(0xFFE3)     // Control_L
+	x.altL = x.findKeycode(0xFFE9)      // Alt_L
+	x.superL = x.findKeycode(0xFFEB)    // Super_L
 	x.backspace = x.findKeycode(0xFF08) // BackSpace
 	x.vKey = x.findKeycode(uint32('v'))
 
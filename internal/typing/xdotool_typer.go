@@ -0,0 +1,159 @@
+package typing
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sync"
+)
+
+// XdotoolTyper types and manages the clipboard via the xdotool and xclip
+// CLIs, for X11 and XWayland sessions.
+type XdotoolTyper struct {
+	EnablePunctuation bool
+	lastRuneCount     int
+
+	punctMu sync.RWMutex
+
+	// x11Once/x11/x11Err lazily open the dedicated XTest connection
+	// PlayMacro replays events on; see macro_playback.go.
+	x11Once sync.Once
+	x11     *x11Typer
+	x11Err  error
+}
+
+func (t *XdotoolTyper) SetEnablePunctuation(v bool) {
+	t.punctMu.Lock()
+	t.EnablePunctuation = v
+	t.punctMu.Unlock()
+}
+
+func (t *XdotoolTyper) Type(text string) error {
+	t.punctMu.RLock()
+	enablePunctuation := t.EnablePunctuation
+	t.punctMu.RUnlock()
+	if enablePunctuation {
+		text = processPunctuation(text)
+	}
+	t.lastRuneCount = len([]rune(text))
+	if t.lastRuneCount >= clipboardThreshold {
+		return t.SetClipboardAndPaste(text)
+	}
+	return exec.Command("xdotool", "type", "--clearmodifiers", "--delay", "0", "--", text).Run()
+}
+
+// TypeIncremental updates the target app's text in place from prev (what's
+// currently typed on screen) to next: it finds their common prefix and sends
+// only the backspaces and insertions needed to converge, instead of
+// retyping the whole phrase. Intended for streaming recognition, where next
+// is a successively refined partial transcript of the same phrase as prev.
+func (t *XdotoolTyper) TypeIncremental(prev, next string) error {
+	t.punctMu.RLock()
+	enablePunctuation := t.EnablePunctuation
+	t.punctMu.RUnlock()
+	if enablePunctuation {
+		// prev must go through the same processing as next did when it was
+		// typed, or commonPrefixLen diffs against text that's not what's
+		// actually on screen.
+		prev = processPunctuation(prev)
+		next = processPunctuation(next)
+	}
+
+	prevRunes := []rune(prev)
+	nextRunes := []rune(next)
+	common := commonPrefixLen(prevRunes, nextRunes)
+
+	if n := len(prevRunes) - common; n > 0 {
+		if err := t.SendBackspaces(n); err != nil {
+			return err
+		}
+	}
+	t.lastRuneCount = len(nextRunes)
+
+	suffix := string(nextRunes[common:])
+	if suffix == "" {
+		return nil
+	}
+	return exec.Command("xdotool", "type", "--clearmodifiers", "--delay", "0", "--", suffix).Run()
+}
+
+func (t *XdotoolTyper) Undo() error {
+	if t.lastRuneCount == 0 {
+		return nil
+	}
+	n := t.lastRuneCount
+	t.lastRuneCount = 0
+	return t.SendBackspaces(n)
+}
+
+// SendBackspaces sends n BackSpace keystrokes as a single xdotool call.
+func (t *XdotoolTyper) SendBackspaces(n int) error {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "BackSpace"
+	}
+	return exec.Command("xdotool", append([]string{"key", "--clearmodifiers", "--delay", "0"}, keys...)...).Run()
+}
+
+// GetClipboard returns the current CLIPBOARD selection contents.
+func (t *XdotoolTyper) GetClipboard() string {
+	out, err := exec.Command("xclip", "-selection", "clipboard", "-o").Output()
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// SetClipboardAndPaste saves the current clipboard, writes text to it,
+// pastes, then restores the original clipboard contents.
+func (t *XdotoolTyper) SetClipboardAndPaste(text string) error {
+	saved, _ := exec.Command("xclip", "-selection", "clipboard", "-o").Output()
+
+	cmd := exec.Command("xclip", "-selection", "clipboard")
+	cmd.Stdin = bytes.NewReader([]byte(text))
+	if err := cmd.Run(); err != nil {
+		// xclip not available — fall back to a direct type.
+		return exec.Command("xdotool", "type", "--clearmodifiers", "--delay", "0", "--", text).Run()
+	}
+
+	if err := exec.Command("xdotool", "key", "--clearmodifiers", "ctrl+v").Run(); err != nil {
+		return err
+	}
+
+	// Restore original clipboard (best-effort).
+	if len(saved) > 0 {
+		restore := exec.Command("xclip", "-selection", "clipboard")
+		restore.Stdin = bytes.NewReader(saved)
+		restore.Run() //nolint:errcheck
+	}
+	return nil
+}
+
+// PlayMacro replays a previously recorded macro through XTest FakeInput on
+// a dedicated X11 connection, preserving each event's recorded delay
+// (capped at maxMacroDelay). Modifier-key events are re-resolved against
+// the playback connection's own keyboard mapping rather than the keycode
+// recorded at capture time, so a macro still plays correctly after a
+// keyboard remap.
+func (t *XdotoolTyper) PlayMacro(name string) error {
+	m, err := LoadMacro(name)
+	if err != nil {
+		return fmt.Errorf("loading macro %q: %w", name, err)
+	}
+
+	x, err := t.macroPlayer()
+	if err != nil {
+		return fmt.Errorf("opening X11 connection for macro playback: %w", err)
+	}
+
+	return playMacroEvents(x, m.Events)
+}
+
+// macroPlayer lazily opens the X11 connection PlayMacro replays events on,
+// reusing it across calls instead of reconnecting per macro.
+func (t *XdotoolTyper) macroPlayer() (*x11Typer, error) {
+	t.x11Once.Do(func() {
+		t.x11, t.x11Err = newX11Typer()
+	})
+	return t.x11, t.x11Err
+}
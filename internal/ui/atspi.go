@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// AT-SPI2's StateType enum values used here. FOCUSED's index determines
+// which bit of the two-word GetState bitmask to test.
+const (
+	atspiStateFocused    = 12
+	atspiCoordTypeScreen = uint32(0)
+)
+
+// atspiAccessible is the AT-SPI2 D-Bus address of one accessible object: a
+// bus name plus an object path on that bus, the "(so)" pair the protocol
+// uses everywhere an Accessible reference is passed.
+type atspiAccessible struct {
+	Bus  string
+	Path dbus.ObjectPath
+}
+
+// atspiCache remembers the last focused accessible across calls so a popup
+// Show while the same widget still has focus can skip the desktop tree walk
+// entirely.
+var atspiCache struct {
+	mu   sync.Mutex
+	last atspiAccessible
+	have bool
+}
+
+// queryCaretViaAtspi reads the focused widget's caret position straight off
+// the AT-SPI2 accessibility bus, replacing the former python3+gi subprocess.
+// It tries the cached last-focused accessible first, then falls back to
+// walking the desktop tree; either way it respects a 500ms budget.
+func queryCaretViaAtspi() (x, y int, ok bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	conn, err := atspiDial(ctx)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer conn.Close()
+
+	atspiCache.mu.Lock()
+	cached, have := atspiCache.last, atspiCache.have
+	atspiCache.mu.Unlock()
+
+	if have {
+		if x, y, ok := atspiCaretExtents(ctx, conn, cached); ok {
+			return x, y, true
+		}
+	}
+
+	focused, ok := atspiFindFocused(ctx, conn)
+	if !ok {
+		return 0, 0, false
+	}
+	atspiCache.mu.Lock()
+	atspiCache.last, atspiCache.have = focused, true
+	atspiCache.mu.Unlock()
+
+	return atspiCaretExtents(ctx, conn, focused)
+}
+
+// atspiDial connects to the accessibility bus: its address isn't fixed, so
+// it must be looked up from the session bus first via org.a11y.Bus.
+func atspiDial(ctx context.Context) (*dbus.Conn, error) {
+	session, err := dbus.ConnectSessionBus(dbus.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	var addr string
+	if err := session.Object("org.a11y.Bus", "/org/a11y/bus").
+		CallWithContext(ctx, "org.a11y.Bus.GetAddress", 0).Store(&addr); err != nil {
+		return nil, err
+	}
+
+	conn, err := dbus.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Auth(nil); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := conn.Hello(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// atspiFindFocused walks the desktop's accessible tree depth-first looking
+// for the FOCUSED state, mirroring the old Python script's find() but over
+// the one D-Bus connection the caller dialed for this query instead of a
+// python3+gi subprocess.
+func atspiFindFocused(ctx context.Context, conn *dbus.Conn) (atspiAccessible, bool) {
+	desktop := atspiAccessible{Bus: "org.a11y.atspi.Registry", Path: "/org/a11y/atspi/accessible/root"}
+	apps, err := atspiChildren(ctx, conn, desktop)
+	if err != nil {
+		return atspiAccessible{}, false
+	}
+	for _, app := range apps {
+		if found, ok := atspiSearch(ctx, conn, app, 0); ok {
+			return found, true
+		}
+	}
+	return atspiAccessible{}, false
+}
+
+func atspiSearch(ctx context.Context, conn *dbus.Conn, a atspiAccessible, depth int) (atspiAccessible, bool) {
+	if depth > 20 {
+		return atspiAccessible{}, false
+	}
+	if atspiIsFocused(ctx, conn, a) {
+		return a, true
+	}
+	children, err := atspiChildren(ctx, conn, a)
+	if err != nil {
+		return atspiAccessible{}, false
+	}
+	for _, c := range children {
+		if found, ok := atspiSearch(ctx, conn, c, depth+1); ok {
+			return found, true
+		}
+	}
+	return atspiAccessible{}, false
+}
+
+func atspiIsFocused(ctx context.Context, conn *dbus.Conn, a atspiAccessible) bool {
+	var state []uint32
+	err := conn.Object(a.Bus, a.Path).
+		CallWithContext(ctx, "org.a11y.atspi.Accessible.GetState", 0).Store(&state)
+	if err != nil || len(state) == 0 {
+		return false
+	}
+	word, bit := atspiStateFocused/32, uint(atspiStateFocused%32)
+	return word < len(state) && state[word]&(1<<bit) != 0
+}
+
+func atspiChildren(ctx context.Context, conn *dbus.Conn, a atspiAccessible) ([]atspiAccessible, error) {
+	var children []atspiAccessible
+	err := conn.Object(a.Bus, a.Path).
+		CallWithContext(ctx, "org.a11y.atspi.Accessible.GetChildren", 0).Store(&children)
+	return children, err
+}
+
+// atspiCaretExtents retrieves (x, y) for a's text caret via the Text
+// interface, falling back to the Component interface's bounding box center
+// for accessibles with no caret (e.g. a focused button).
+func atspiCaretExtents(ctx context.Context, conn *dbus.Conn, a atspiAccessible) (x, y int, ok bool) {
+	obj := conn.Object(a.Bus, a.Path)
+
+	var offset int32
+	if err := obj.CallWithContext(ctx, "org.a11y.atspi.Text.GetCaretOffset", 0).Store(&offset); err == nil {
+		var ex, ey, ew, eh int32
+		call := obj.CallWithContext(ctx, "org.a11y.atspi.Text.GetCharacterExtents", 0, offset, atspiCoordTypeScreen)
+		if err := call.Store(&ex, &ey, &ew, &eh); err == nil && (ex > 0 || ey > 0) {
+			return int(ex), int(ey), true
+		}
+	}
+
+	var ex, ey, ew, eh int32
+	call := obj.CallWithContext(ctx, "org.a11y.atspi.Component.GetExtents", 0, atspiCoordTypeScreen)
+	if err := call.Store(&ex, &ey, &ew, &eh); err == nil && ew > 0 {
+		return int(ex) + int(ew)/2, int(ey), true
+	}
+
+	return 0, 0, false
+}
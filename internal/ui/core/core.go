@@ -0,0 +1,155 @@
+// Package core holds UI-toolkit-agnostic state shared by every
+// gotalk-dictation frontend: the language list, the settings-form diffing
+// logic, and the hotkey-capture state machine. Keeping this out of
+// internal/ui's Fyne-specific code lets internal/ui/tui (tcell) reuse it
+// without linking Fyne.
+package core
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/Alijeyrad/gotalk-dictation/internal/config"
+)
+
+// Language pairs a BCP-47 code with a human-readable label for the settings form.
+type Language struct {
+	Code, Label string
+}
+
+// Languages is the full list offered by every frontend's language picker.
+var Languages = []Language{
+	{"fa-IR", "Persian (Farsi)"},
+	{"en-US", "English (US)"},
+	{"en-GB", "English (UK)"},
+	{"es-ES", "Spanish (Spain)"},
+	{"es-MX", "Spanish (Mexico)"},
+	{"es-419", "Spanish (Latin America)"},
+	{"fr-FR", "French (France)"},
+	{"fr-CA", "French (Canada)"},
+	{"de-DE", "German"},
+	{"it-IT", "Italian"},
+	{"pt-PT", "Portuguese (Portugal)"},
+	{"pt-BR", "Portuguese (Brazil)"},
+	{"ar-SA", "Arabic (Saudi Arabia)"},
+	{"ar-EG", "Arabic (Egypt)"},
+	{"zh-CN", "Chinese (Simplified)"},
+	{"zh-TW", "Chinese (Traditional)"},
+	{"nl-NL", "Dutch"},
+	{"hi-IN", "Hindi"},
+	{"ja-JP", "Japanese"},
+	{"ko-KR", "Korean"},
+	{"pl-PL", "Polish"},
+	{"ru-RU", "Russian"},
+	{"sv-SE", "Swedish"},
+	{"tr-TR", "Turkish"},
+	{"uk-UA", "Ukrainian"},
+}
+
+// LabelsAndCodes builds the label list and label<->code maps a select-style
+// widget needs, in Languages order.
+func LabelsAndCodes() (labels []string, labelToCode, codeToLabel map[string]string) {
+	labels = make([]string, len(Languages))
+	labelToCode = make(map[string]string, len(Languages))
+	codeToLabel = make(map[string]string, len(Languages))
+	for i, l := range Languages {
+		labels[i] = l.Label
+		labelToCode[l.Label] = l.Code
+		codeToLabel[l.Code] = l.Label
+	}
+	return labels, labelToCode, codeToLabel
+}
+
+// Changed reports whether draft differs from orig in any field a settings
+// form can edit. Frontends build draft from their own widget state (see
+// ui.showSettingsWindow's doSave, or tui.Console's equivalent) and call
+// Changed to decide whether the Save control should be enabled.
+func Changed(orig, draft *config.Config) bool {
+	return draft.Hotkey != orig.Hotkey ||
+		draft.UndoHotkey != orig.UndoHotkey ||
+		draft.PTTHotkey != orig.PTTHotkey ||
+		draft.CycleProfileHotkey != orig.CycleProfileHotkey ||
+		draft.Language != orig.Language ||
+		draft.Timeout != orig.Timeout ||
+		draft.SilenceChunks != orig.SilenceChunks ||
+		draft.Sensitivity != orig.Sensitivity ||
+		draft.APIKey != orig.APIKey ||
+		draft.UseAdvancedAPI != orig.UseAdvancedAPI ||
+		draft.Backend != orig.Backend ||
+		draft.Streaming != orig.Streaming ||
+		draft.WhisperModelSize != orig.WhisperModelSize ||
+		draft.Model != orig.Model ||
+		draft.EnableAutomaticPunctuation != orig.EnableAutomaticPunctuation ||
+		draft.UseEnhanced != orig.UseEnhanced ||
+		draft.ProfanityFilter != orig.ProfanityFilter ||
+		strings.Join(draft.AlternativeLanguageCodes, ",") != strings.Join(orig.AlternativeLanguageCodes, ",") ||
+		draft.MaxAlternatives != orig.MaxAlternatives ||
+		draft.EnableWordTimeOffsets != orig.EnableWordTimeOffsets ||
+		draft.EnableWordConfidence != orig.EnableWordConfidence ||
+		draft.LongForm != orig.LongForm ||
+		draft.GCSBucket != orig.GCSBucket ||
+		!reflect.DeepEqual(draft.Vocabulary, orig.Vocabulary) ||
+		draft.EnablePunctuation != orig.EnablePunctuation ||
+		draft.SoundEnabled != orig.SoundEnabled ||
+		draft.NotifyEnabled != orig.NotifyEnabled ||
+		draft.PopupNotify != orig.PopupNotify ||
+		draft.SoundStart != orig.SoundStart ||
+		draft.SoundStop != orig.SoundStop ||
+		draft.SoundError != orig.SoundError ||
+		draft.SoundUndo != orig.SoundUndo
+}
+
+// ModMask is a toolkit-agnostic bitmask for the modifier keys a hotkey
+// capture can hold down. Fyne and tcell each report modifiers differently;
+// frontends translate their own key events into these before driving a
+// HotkeyCapture.
+type ModMask int
+
+const (
+	ModControl ModMask = 1 << iota
+	ModAlt
+	ModShift
+	ModSuper
+)
+
+// HotkeyCapture accumulates held modifiers across a key-down/key-up sequence
+// and resolves them plus a trailing key name into a hotkey string in the
+// format hotkey.Bindings.Bind parses (e.g. "Alt-d"). It has no dependency on any UI
+// toolkit, so both the Fyne settings window and the tcell TUI drive the same
+// state machine from their own native key events.
+type HotkeyCapture struct {
+	held ModMask
+}
+
+// ModifierDown marks m as currently held.
+func (c *HotkeyCapture) ModifierDown(m ModMask) { c.held |= m }
+
+// ModifierUp marks m as released.
+func (c *HotkeyCapture) ModifierUp(m ModMask) { c.held &^= m }
+
+// Reset clears all held modifiers, e.g. when capture is cancelled.
+func (c *HotkeyCapture) Reset() { c.held = 0 }
+
+// Resolve builds a hotkey string from the currently held modifiers plus
+// keyName (expected lowercase, non-modifier). ok is false if none of
+// Alt/Ctrl/Super is held — Shift alone is not a valid global hotkey modifier.
+func (c *HotkeyCapture) Resolve(keyName string) (hotkey string, ok bool) {
+	if c.held&(ModAlt|ModControl|ModSuper) == 0 {
+		return "", false
+	}
+	var parts []string
+	if c.held&ModControl != 0 {
+		parts = append(parts, "Ctrl")
+	}
+	if c.held&ModAlt != 0 {
+		parts = append(parts, "Alt")
+	}
+	if c.held&ModShift != 0 {
+		parts = append(parts, "Shift")
+	}
+	if c.held&ModSuper != 0 {
+		parts = append(parts, "Super")
+	}
+	parts = append(parts, keyName)
+	return strings.Join(parts, "-"), true
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/Alijeyrad/gotalk-dictation/internal/config"
+)
+
+func TestLabelsAndCodesRoundTrip(t *testing.T) {
+	labels, labelToCode, codeToLabel := LabelsAndCodes()
+	if len(labels) != len(Languages) {
+		t.Fatalf("got %d labels, want %d", len(labels), len(Languages))
+	}
+	for _, l := range Languages {
+		if labelToCode[l.Label] != l.Code {
+			t.Errorf("labelToCode[%q] = %q, want %q", l.Label, labelToCode[l.Label], l.Code)
+		}
+		if codeToLabel[l.Code] != l.Label {
+			t.Errorf("codeToLabel[%q] = %q, want %q", l.Code, codeToLabel[l.Code], l.Label)
+		}
+	}
+}
+
+func TestChangedNoDiff(t *testing.T) {
+	cfg := config.Default()
+	draft := *cfg
+	if Changed(cfg, &draft) {
+		t.Error("Changed reported a diff between identical configs")
+	}
+}
+
+func TestChangedDetectsFieldDiff(t *testing.T) {
+	cfg := config.Default()
+	draft := *cfg
+	draft.Sensitivity = cfg.Sensitivity + 1
+	if !Changed(cfg, &draft) {
+		t.Error("Changed did not detect a Sensitivity diff")
+	}
+}
+
+func TestHotkeyCaptureRequiresNonShiftModifier(t *testing.T) {
+	var c HotkeyCapture
+	c.ModifierDown(ModShift)
+	if _, ok := c.Resolve("d"); ok {
+		t.Error("Resolve should fail with only Shift held")
+	}
+}
+
+func TestHotkeyCaptureResolvesComboInCanonicalOrder(t *testing.T) {
+	var c HotkeyCapture
+	c.ModifierDown(ModShift)
+	c.ModifierDown(ModAlt)
+	c.ModifierDown(ModControl)
+	got, ok := c.Resolve("d")
+	if !ok {
+		t.Fatal("Resolve should succeed with Ctrl+Alt+Shift held")
+	}
+	if want := "Ctrl-Alt-Shift-d"; got != want {
+		t.Errorf("Resolve() = %q, want %q", got, want)
+	}
+}
+
+func TestHotkeyCaptureModifierUpAndReset(t *testing.T) {
+	var c HotkeyCapture
+	c.ModifierDown(ModAlt)
+	c.ModifierUp(ModAlt)
+	if _, ok := c.Resolve("d"); ok {
+		t.Error("Resolve should fail after the only modifier was released")
+	}
+
+	c.ModifierDown(ModSuper)
+	c.Reset()
+	if _, ok := c.Resolve("d"); ok {
+		t.Error("Resolve should fail after Reset")
+	}
+}
@@ -0,0 +1,120 @@
+// Package headless exposes a line-delimited JSON-RPC control socket for
+// gotalk-dictation when no display and no terminal are available at all
+// (e.g. a server running the daemon under systemd). Each connection sends
+// one {"id":1,"method":"start"} request per line and gets back one
+// {"id":1,"result":...} or {"id":1,"error":"..."} line.
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/Alijeyrad/gotalk-dictation/internal/config"
+)
+
+// Request is one line of input.
+type Request struct {
+	ID     int             `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is one line of output, answering the Request with the same ID.
+type Response struct {
+	ID     int         `json:"id"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// Handlers are the daemon operations a headless client can invoke. A nil
+// handler answers its method with an error instead of panicking.
+type Handlers struct {
+	Start     func()
+	Stop      func()
+	Status    func() string
+	GetConfig func() *config.Config
+	SetConfig func(*config.Config) error
+}
+
+// Serve listens on socketPath and answers requests until the listener is
+// closed or Accept fails. Removes any stale socket file left by a previous run.
+func Serve(socketPath string, h Handlers) error {
+	os.Remove(socketPath) //nolint:errcheck
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", socketPath, err)
+	}
+	defer ln.Close()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go handleConn(conn, h)
+	}
+}
+
+func handleConn(conn net.Conn, h Handlers) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			enc.Encode(Response{Error: "invalid JSON: " + err.Error()}) //nolint:errcheck
+			continue
+		}
+		enc.Encode(dispatch(req, h)) //nolint:errcheck
+	}
+}
+
+func dispatch(req Request, h Handlers) Response {
+	resp := Response{ID: req.ID}
+	switch req.Method {
+	case "start":
+		if h.Start == nil {
+			resp.Error = "start not supported"
+			return resp
+		}
+		h.Start()
+	case "stop":
+		if h.Stop == nil {
+			resp.Error = "stop not supported"
+			return resp
+		}
+		h.Stop()
+	case "status":
+		if h.Status == nil {
+			resp.Error = "status not supported"
+			return resp
+		}
+		resp.Result = h.Status()
+	case "get_config":
+		if h.GetConfig == nil {
+			resp.Error = "get_config not supported"
+			return resp
+		}
+		resp.Result = h.GetConfig()
+	case "set_config":
+		if h.SetConfig == nil {
+			resp.Error = "set_config not supported"
+			return resp
+		}
+		var cfg config.Config
+		if err := json.Unmarshal(req.Params, &cfg); err != nil {
+			resp.Error = "invalid config: " + err.Error()
+			return resp
+		}
+		if err := h.SetConfig(&cfg); err != nil {
+			resp.Error = err.Error()
+		}
+	default:
+		resp.Error = "unknown method: " + req.Method
+	}
+	return resp
+}
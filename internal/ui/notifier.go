@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"context"
+	"os/exec"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Notifier bridges the popup's Done/Error states to a desktop notification,
+// so a headless, locked-screen, or different-workspace session still sees
+// the result even though the popup itself is invisible there.
+type Notifier interface {
+	Done(text string)
+	Error(msg string)
+}
+
+// Notification urgency levels, per the freedesktop Notifications spec's
+// "urgency" hint (a byte: 0=low, 1=normal, 2=critical).
+const (
+	notifyUrgencyLow      = byte(0)
+	notifyUrgencyCritical = byte(2)
+)
+
+// DBusNotifier calls org.freedesktop.Notifications.Notify directly over the
+// session bus (via godbus) rather than shelling out to notify-send, so it
+// can set the urgency and expire-timeout hints notify-send doesn't expose.
+type DBusNotifier struct {
+	// PlaySound additionally plays a short audio cue on Error, since a
+	// missed error is worse than a missed success.
+	PlaySound bool
+}
+
+// Done sends a low-urgency notification that expires after 2 seconds, with
+// the transcribed text as its body.
+func (n *DBusNotifier) Done(text string) {
+	if text == "" {
+		text = "Done"
+	}
+	notifyDBus("GoTalk Dictation", text, notifyUrgencyLow, 2000)
+}
+
+// Error sends a critical, non-expiring notification, optionally paired with
+// an audible cue.
+func (n *DBusNotifier) Error(msg string) {
+	notifyDBus("GoTalk Dictation", "Error: "+msg, notifyUrgencyCritical, 0)
+	if n.PlaySound {
+		go playErrorSound()
+	}
+}
+
+// notifyDBus is best-effort: a missing session bus or notification daemon
+// must never block or fail dictation, so every error is silently dropped.
+func notifyDBus(summary, body string, urgency byte, expireMS int32) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+
+		conn, err := dbus.ConnectSessionBus(dbus.WithContext(ctx))
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		hints := map[string]dbus.Variant{"urgency": dbus.MakeVariant(urgency)}
+		obj := conn.Object("org.freedesktop.Notifications", dbus.ObjectPath("/org/freedesktop/Notifications"))
+		obj.CallWithContext(ctx, "org.freedesktop.Notifications.Notify", 0, //nolint:errcheck
+			"GoTalk Dictation", uint32(0), "", summary, body, []string{}, hints, expireMS)
+	}()
+}
+
+// playErrorSound tries canberra-gtk-play (libcanberra's ca_context_play,
+// wrapped in a CLI tool) first, falling back to paplay with the desktop
+// theme's error sound if canberra-gtk-play isn't installed.
+func playErrorSound() {
+	if err := exec.Command("canberra-gtk-play", "-i", "dialog-error").Run(); err == nil {
+		return
+	}
+	exec.Command("paplay", "/usr/share/sounds/freedesktop/stereo/dialog-error.oga").Run() //nolint:errcheck
+}
@@ -1,7 +1,6 @@
 package ui
 
 import (
-	"context"
 	"fmt"
 	"math"
 	"os/exec"
@@ -162,6 +161,29 @@ func (p *X11Popup) SetState(s popState) {
 	p.mu.Unlock()
 }
 
+// SetPartial updates the popup's text preview to the evolving partial
+// transcript from streaming recognition, so words appear as the user speaks
+// instead of only once the phrase is done. Unlike ShowDone it leaves the
+// current state (stListening/stProcessing) alone — only the preview text
+// and window width change. Only ASCII text is rendered; pass "" to clear it.
+func (p *X11Popup) SetPartial(text string) {
+	if !p.hasFont {
+		return
+	}
+	preview := asciiPreview(text, 24)
+
+	p.mu.Lock()
+	p.preview = preview
+	p.mu.Unlock()
+
+	w := uint32(popSz)
+	if preview != "" {
+		w = uint32(popSz + len(preview)*7 + 10)
+	}
+	xproto.ConfigureWindow(p.conn, p.wid, //nolint:errcheck
+		xproto.ConfigWindowWidth|xproto.ConfigWindowHeight, []uint32{w, popSz})
+}
+
 // ShowDone switches to the Done state and displays a short text preview.
 // Only ASCII text is rendered; non-ASCII falls back to the green circle.
 func (p *X11Popup) ShowDone(text string) {
@@ -275,12 +297,16 @@ func (p *X11Popup) eventLoop() {
 func (p *X11Popup) drawFrame(s popState, preview string, frame int) {
 	d := xproto.Drawable(p.wid)
 	clearW, clearH := uint16(popSz), uint16(popSz)
-	if preview != "" && s == stDone {
-		clearW = uint16(len(preview)*7 + 20)
-		if clearW < 80 {
-			clearW = 80
+	if preview != "" {
+		if s == stDone {
+			clearW = uint16(len(preview)*7 + 20)
+			if clearW < 80 {
+				clearW = 80
+			}
+			clearH = 28
+		} else {
+			clearW = uint16(popSz + len(preview)*7 + 10)
 		}
-		clearH = 28
 	}
 	p.setFG(popBG)
 	xproto.PolyFillRectangle(p.conn, d, p.gc, //nolint:errcheck
@@ -288,9 +314,9 @@ func (p *X11Popup) drawFrame(s popState, preview string, frame int) {
 
 	switch s {
 	case stListening:
-		p.drawListening(frame)
+		p.drawListening(frame, preview)
 	case stProcessing:
-		p.drawProcessing(frame)
+		p.drawProcessing(frame, preview)
 	case stDone:
 		p.drawFlash(0x30D158, preview)
 	case stError:
@@ -298,13 +324,23 @@ func (p *X11Popup) drawFrame(s popState, preview string, frame int) {
 	}
 }
 
-func (p *X11Popup) drawListening(frame int) {
+func (p *X11Popup) drawListening(frame int, preview string) {
 	t := float64(frame) * 2 * math.Pi / 40
 	r := int(12 + 5*math.Sin(t))
 	p.fillCircle(popCX, popCY, r, 0xFF3B30)
+	p.drawPreviewText(preview)
 }
 
-func (p *X11Popup) drawProcessing(frame int) {
+// drawPreviewText renders the partial-transcript preview to the right of
+// whichever animation is currently drawn, e.g. during SetPartial updates.
+func (p *X11Popup) drawPreviewText(preview string) {
+	if preview == "" || !p.hasFont {
+		return
+	}
+	xproto.ImageText8(p.conn, uint8(len(preview)), xproto.Drawable(p.wid), p.textGC, popSz+6, popCY+5, preview) //nolint:errcheck
+}
+
+func (p *X11Popup) drawProcessing(frame int, preview string) {
 	const (
 		arcR    = uint16(17)
 		lineW   = uint32(4)
@@ -330,6 +366,7 @@ func (p *X11Popup) drawProcessing(frame int) {
 		X: arcX, Y: arcY, Width: arcWH, Height: arcWH,
 		Angle1: deg * 64, Angle2: sweep,
 	}})
+	p.drawPreviewText(preview)
 }
 
 func (p *X11Popup) drawFlash(color uint32, preview string) {
@@ -381,74 +418,6 @@ func (p *X11Popup) queryCaretPos() (int16, int16) {
 	return int16(p.screen.WidthInPixels / 2), int16(p.screen.HeightInPixels / 2)
 }
 
-// queryCaretViaAtspi shells out to python3+gi to read the focused widget's
-// caret position from the AT-SPI2 accessibility tree.
-func queryCaretViaAtspi() (x, y int, ok bool) {
-	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
-	defer cancel()
-
-	out, err := exec.CommandContext(ctx, "python3", "-c", atspiScript).Output()
-	if err != nil {
-		return 0, 0, false
-	}
-	parts := strings.Fields(strings.TrimSpace(string(out)))
-	if len(parts) != 2 {
-		return 0, 0, false
-	}
-	px, err1 := strconv.Atoi(parts[0])
-	py, err2 := strconv.Atoi(parts[1])
-	if err1 != nil || err2 != nil {
-		return 0, 0, false
-	}
-	return px, py, true
-}
-
-const atspiScript = `
-import sys
-try:
-    import gi
-    gi.require_version('Atspi', '2.0')
-    from gi.repository import Atspi
-    desktop = Atspi.get_desktop(0)
-    def find(obj, d=0):
-        if d > 20: return None
-        try:
-            ss = obj.get_state_set()
-            if ss.contains(Atspi.StateType.FOCUSED):
-                try:
-                    t = obj.get_text_iface()
-                    if t:
-                        o = t.get_caret_offset()
-                        e = t.get_character_extents(o, Atspi.CoordType.SCREEN)
-                        if e.x > 0 or e.y > 0:
-                            return (e.x, e.y)
-                except: pass
-                try:
-                    c = obj.get_component_iface()
-                    if c:
-                        e = c.get_extents(Atspi.CoordType.SCREEN)
-                        if e.width > 0:
-                            return (e.x + e.width // 2, e.y)
-                except: pass
-            for j in range(obj.get_child_count()):
-                ch = obj.get_child_at_index(j)
-                if ch:
-                    r = find(ch, d + 1)
-                    if r: return r
-        except: pass
-        return None
-    for i in range(desktop.get_child_count()):
-        app = desktop.get_child_at_index(i)
-        if app:
-            r = find(app)
-            if r:
-                print(r[0], r[1])
-                sys.exit(0)
-    sys.exit(1)
-except:
-    sys.exit(1)
-`
-
 func parseShellVars(s string) map[string]int {
 	m := make(map[string]int)
 	for _, line := range strings.Split(s, "\n") {
@@ -0,0 +1,193 @@
+// Package repl implements an interactive terminal control surface for
+// gotalk-dictation: a peterh/liner prompt where the configured PTT hotkey's
+// recognized transcript lands as editable text at the cursor instead of
+// being typed into whatever window has focus, plus slash commands for
+// changing settings without opening the tray settings window. It's a third
+// way to drive the same typer/recognizer entry points as ui.Tray and
+// tui.Console — for terminal-only sessions (tmux, SSH, a headless box).
+package repl
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/peterh/liner"
+)
+
+// prompt is shown at the start of every input line.
+const prompt = "🎙 > "
+
+// historyPath returns where transcript/command history is persisted
+// between runs.
+func historyPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "share", "gotalk-dictation", "history")
+}
+
+// Handlers are the daemon operations the REPL's slash commands and Enter
+// key invoke. A nil handler silently ignores the command.
+type Handlers struct {
+	// Type commits text the same way the tray's auto-type does: through
+	// typer.Type, so the same clipboard-vs-xdotool threshold logic applies.
+	Type func(text string) error
+	// Undo calls Typer.Undo, bound to both "/undo" and Ctrl-Z.
+	Undo func()
+	// SetLanguage and SetPunctuation back "/lang <code>" and
+	// "/punct on|off".
+	SetLanguage    func(lang string)
+	SetPunctuation func(on bool)
+	// RunMacro replays a recorded macro, backing "/macro <name>".
+	RunMacro func(name string) error
+}
+
+// Console is the REPL frontend. Construct with New, wire the app's
+// transcript sink to OfferTranscript, then call Run — it blocks until the
+// user quits (Ctrl-D or "/quit").
+type Console struct {
+	h           Handlers
+	line        *liner.State
+	transcripts chan string
+}
+
+// New builds a Console that dispatches slash commands and committed lines
+// to h.
+func New(h Handlers) *Console {
+	return &Console{h: h, transcripts: make(chan string, 1)}
+}
+
+// OfferTranscript is called by the dictation pipeline once a phrase has
+// been recognized; it queues the text so Run's prompt loop preloads it into
+// the line buffer for editing, instead of the pipeline typing it directly.
+// A transcript that arrives while one is already queued and unconsumed is
+// dropped, the same as a missed PTT toggle would be.
+func (c *Console) OfferTranscript(text string) {
+	select {
+	case c.transcripts <- text:
+	default:
+	}
+}
+
+// Run starts the prompt loop. It blocks until the user quits with Ctrl-D,
+// Ctrl-C, or "/quit".
+func (c *Console) Run() {
+	c.line = liner.NewLiner()
+	defer c.line.Close()
+	c.line.SetCtrlCAborts(true)
+
+	if f, err := os.Open(historyPath()); err == nil {
+		c.line.ReadHistory(f) //nolint:errcheck
+		f.Close()
+	}
+	defer c.saveHistory()
+
+	// Catching SIGTSTP ourselves, rather than leaving the terminal's default
+	// handling in place, turns Ctrl-Z into "undo" instead of suspending the
+	// process — there's no portable way to bind a raw key like Ctrl-Z
+	// through liner directly.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTSTP)
+	defer signal.Stop(sigCh)
+	go func() {
+		for range sigCh {
+			c.undo()
+		}
+	}()
+
+	fmt.Println("gotalk-dictation REPL — hold the PTT hotkey to dictate, /help for commands, Ctrl-D to quit")
+
+	for {
+		preload := ""
+		select {
+		case preload = <-c.transcripts:
+		default:
+		}
+
+		line, err := c.line.PromptWithSuggestion(prompt, preload, len(preload))
+		if err != nil { // io.EOF (Ctrl-D) or liner.ErrPromptAborted (Ctrl-C)
+			return
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		c.line.AppendHistory(line)
+
+		if strings.HasPrefix(line, "/") {
+			if c.runCommand(line) {
+				return
+			}
+			continue
+		}
+
+		if c.h.Type != nil {
+			if err := c.h.Type(line); err != nil {
+				fmt.Println("type error:", err)
+			}
+		}
+	}
+}
+
+// runCommand dispatches a "/word [arg]" line and reports whether the user
+// asked to quit.
+func (c *Console) runCommand(line string) bool {
+	fields := strings.Fields(line)
+	cmd, arg := fields[0], ""
+	if len(fields) > 1 {
+		arg = strings.Join(fields[1:], " ")
+	}
+
+	switch cmd {
+	case "/lang":
+		if c.h.SetLanguage != nil && arg != "" {
+			c.h.SetLanguage(arg)
+			fmt.Println("language:", arg)
+		}
+	case "/punct":
+		if c.h.SetPunctuation != nil {
+			on := arg == "on" || arg == "true" || arg == "1"
+			c.h.SetPunctuation(on)
+			fmt.Println("punctuation:", on)
+		}
+	case "/macro":
+		if c.h.RunMacro != nil && arg != "" {
+			if err := c.h.RunMacro(arg); err != nil {
+				fmt.Println("macro error:", err)
+			}
+		}
+	case "/undo":
+		c.undo()
+	case "/quit":
+		return true
+	case "/help":
+		fmt.Println("/lang <code>   /punct on|off   /macro <name>   /undo (or Ctrl-Z)   /quit")
+	default:
+		fmt.Println("unknown command:", cmd, "— /help for the list")
+	}
+	return false
+}
+
+func (c *Console) undo() {
+	if c.h.Undo == nil {
+		return
+	}
+	c.h.Undo()
+	fmt.Println("undo")
+}
+
+func (c *Console) saveHistory() {
+	path := historyPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	c.line.WriteHistory(f) //nolint:errcheck
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -14,35 +15,33 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"github.com/Alijeyrad/gotalk-dictation/internal/config"
+	"github.com/Alijeyrad/gotalk-dictation/internal/ui/core"
 	"github.com/Alijeyrad/gotalk-dictation/internal/version"
 )
 
-var languages = []struct{ code, label string }{
-	{"fa-IR", "Persian (Farsi)"},
-	{"en-US", "English (US)"},
-	{"en-GB", "English (UK)"},
-	{"es-ES", "Spanish (Spain)"},
-	{"es-MX", "Spanish (Mexico)"},
-	{"es-419", "Spanish (Latin America)"},
-	{"fr-FR", "French (France)"},
-	{"fr-CA", "French (Canada)"},
-	{"de-DE", "German"},
-	{"it-IT", "Italian"},
-	{"pt-PT", "Portuguese (Portugal)"},
-	{"pt-BR", "Portuguese (Brazil)"},
-	{"ar-SA", "Arabic (Saudi Arabia)"},
-	{"ar-EG", "Arabic (Egypt)"},
-	{"zh-CN", "Chinese (Simplified)"},
-	{"zh-TW", "Chinese (Traditional)"},
-	{"nl-NL", "Dutch"},
-	{"hi-IN", "Hindi"},
-	{"ja-JP", "Japanese"},
-	{"ko-KR", "Korean"},
-	{"pl-PL", "Polish"},
-	{"ru-RU", "Russian"},
-	{"sv-SE", "Swedish"},
-	{"tr-TR", "Turkish"},
-	{"uk-UA", "Ukrainian"},
+// vocabToLines renders a vocabulary list as one phrase per line for the
+// multi-line entry widget; boost isn't shown per-line since the form has a
+// single shared boost slider for the whole list.
+func vocabToLines(vocab []config.VocabPhrase) string {
+	lines := make([]string, len(vocab))
+	for i, v := range vocab {
+		lines[i] = v.Phrase
+	}
+	return strings.Join(lines, "\n")
+}
+
+// linesToVocab parses the multi-line entry back into a vocabulary list,
+// applying boost to every phrase and dropping blank lines.
+func linesToVocab(text string, boost float64) []config.VocabPhrase {
+	var vocab []config.VocabPhrase
+	for _, line := range strings.Split(text, "\n") {
+		phrase := strings.TrimSpace(line)
+		if phrase == "" {
+			continue
+		}
+		vocab = append(vocab, config.VocabPhrase{Phrase: phrase, Boost: boost})
+	}
+	return vocab
 }
 
 func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*config.Config)) fyne.Window {
@@ -50,14 +49,7 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 	w.SetIcon(fyne.NewStaticResource("icon.png", iconPNG))
 	w.Resize(fyne.NewSize(480, 520))
 
-	langLabels := make([]string, len(languages))
-	labelToCode := make(map[string]string, len(languages))
-	codeToLabel := make(map[string]string, len(languages))
-	for i, l := range languages {
-		langLabels[i] = l.label
-		labelToCode[l.label] = l.code
-		codeToLabel[l.code] = l.label
-	}
+	langLabels, labelToCode, codeToLabel := core.LabelsAndCodes()
 
 	initialLabel := codeToLabel[cfg.Language]
 	if initialLabel == "" {
@@ -77,6 +69,119 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 		fyne.TextAlignLeading, fyne.TextStyle{Italic: true},
 	)
 
+	backendLabels := []string{"Free (online)", "Google Cloud (online)", "Whisper (offline)"}
+	backendToCode := map[string]string{
+		"Free (online)":         config.BackendGoogleFree,
+		"Google Cloud (online)": config.BackendGoogleCloud,
+		"Whisper (offline)":     config.BackendWhisperLocal,
+	}
+	codeToBackend := map[string]string{
+		config.BackendGoogleFree:   "Free (online)",
+		config.BackendGoogleCloud:  "Google Cloud (online)",
+		config.BackendWhisperLocal: "Whisper (offline)",
+	}
+	initialBackend := codeToBackend[cfg.Backend]
+	if initialBackend == "" {
+		initialBackend = backendLabels[0]
+	}
+	backendSelect := widget.NewSelect(backendLabels, nil)
+	backendSelect.SetSelected(initialBackend)
+
+	modelSizeLabels := []string{"tiny.en", "base.en", "small.en", "medium.en"}
+	modelSizeSelect := widget.NewSelect(modelSizeLabels, nil)
+	initialModelSize := cfg.WhisperModelSize
+	if initialModelSize == "" {
+		initialModelSize = "base.en"
+	}
+	modelSizeSelect.SetSelected(initialModelSize)
+	modelSizeNote := widget.NewLabelWithStyle(
+		"Downloaded to ~/.cache/gotalk-dictation/models on first use",
+		fyne.TextAlignLeading, fyne.TextStyle{Italic: true},
+	)
+	if backendSelect.Selected != "Whisper (offline)" {
+		modelSizeSelect.Disable()
+	}
+
+	streamingCheck := widget.NewCheck("Type partial results as they arrive", nil)
+	streamingCheck.SetChecked(cfg.Streaming)
+	streamingNote := widget.NewLabelWithStyle(
+		"Only takes effect with the Google Cloud backend",
+		fyne.TextAlignLeading, fyne.TextStyle{Italic: true},
+	)
+	if backendSelect.Selected != "Google Cloud (online)" {
+		streamingCheck.Disable()
+	}
+
+	cloudModelLabels := []string{"(default)", "command_and_search", "latest_long", "phone_call"}
+	cloudModelSelect := widget.NewSelect(cloudModelLabels, nil)
+	initialCloudModel := cfg.Model
+	if initialCloudModel == "" {
+		initialCloudModel = "(default)"
+	}
+	cloudModelSelect.SetSelected(initialCloudModel)
+
+	autoPunctCheck := widget.NewCheck("Automatic punctuation", nil)
+	autoPunctCheck.SetChecked(cfg.EnableAutomaticPunctuation)
+
+	enhancedCheck := widget.NewCheck("Use enhanced model", nil)
+	enhancedCheck.SetChecked(cfg.UseEnhanced)
+
+	profanityCheck := widget.NewCheck("Filter profanity", nil)
+	profanityCheck.SetChecked(cfg.ProfanityFilter)
+
+	altLangEntry := widget.NewEntry()
+	altLangEntry.SetText(strings.Join(cfg.AlternativeLanguageCodes, ", "))
+	altLangEntry.SetPlaceHolder("Comma-separated BCP-47 codes, e.g. es-ES, fr-FR")
+
+	maxAltEntry := widget.NewEntry()
+	maxAltEntry.SetText(strconv.Itoa(int(cfg.MaxAlternatives)))
+	maxAltEntry.SetPlaceHolder("1")
+
+	wordTimingCheck := widget.NewCheck("Word-level timestamps", nil)
+	wordTimingCheck.SetChecked(cfg.EnableWordTimeOffsets)
+
+	wordConfidenceCheck := widget.NewCheck("Word-level confidence", nil)
+	wordConfidenceCheck.SetChecked(cfg.EnableWordConfidence)
+
+	longFormCheck := widget.NewCheck("Long-form dictation (rotate streams past 5 minutes)", nil)
+	longFormCheck.SetChecked(cfg.LongForm)
+
+	gcsBucketEntry := widget.NewEntry()
+	gcsBucketEntry.SetText(cfg.GCSBucket)
+	gcsBucketEntry.SetPlaceHolder("Bucket name, for transcribing recorded files")
+
+	cloudNote := widget.NewLabelWithStyle(
+		"Only takes effect with the Google Cloud backend",
+		fyne.TextAlignLeading, fyne.TextStyle{Italic: true},
+	)
+	disableCloudOnlyWidgets := func() {
+		cloudModelSelect.Disable()
+		autoPunctCheck.Disable()
+		enhancedCheck.Disable()
+		profanityCheck.Disable()
+		altLangEntry.Disable()
+		maxAltEntry.Disable()
+		wordTimingCheck.Disable()
+		wordConfidenceCheck.Disable()
+		longFormCheck.Disable()
+		gcsBucketEntry.Disable()
+	}
+	enableCloudOnlyWidgets := func() {
+		cloudModelSelect.Enable()
+		autoPunctCheck.Enable()
+		enhancedCheck.Enable()
+		profanityCheck.Enable()
+		altLangEntry.Enable()
+		maxAltEntry.Enable()
+		wordTimingCheck.Enable()
+		wordConfidenceCheck.Enable()
+		longFormCheck.Enable()
+		gcsBucketEntry.Enable()
+	}
+	if backendSelect.Selected != "Google Cloud (online)" {
+		disableCloudOnlyWidgets()
+	}
+
 	silenceLabel := widget.NewLabel(fmt.Sprintf("%.0f chunks (~%.0f ms)",
 		float64(cfg.SilenceChunks), float64(cfg.SilenceChunks)*62))
 	silenceSlider := widget.NewSlider(4, 32)
@@ -90,6 +195,7 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 	currentHotkey := cfg.Hotkey
 	currentUndoHotkey := cfg.UndoHotkey
 	currentPTTHotkey := cfg.PTTHotkey
+	currentCycleProfileHotkey := cfg.CycleProfileHotkey
 
 	pttBtnLabel := func(h string) string {
 		if h == "" {
@@ -102,15 +208,15 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 	// reference it; the body is assigned further below.
 	var updateSaveBtn func()
 
-	// heldMods tracks pressed modifiers manually because
+	// capture tracks pressed modifiers manually because
 	// desktop.Driver.CurrentKeyModifiers() is unreliable on KDE Wayland.
-	var heldMods fyne.KeyModifier
+	var capture core.HotkeyCapture
 	// activeCapture points to the button currently waiting for a key combination.
 	var activeCapture *widget.Button
 
 	stopCapture := func() {
 		activeCapture = nil
-		heldMods = 0
+		capture.Reset()
 		if dc, ok := w.Canvas().(desktop.Canvas); ok {
 			dc.SetOnKeyDown(nil)
 			dc.SetOnKeyUp(nil)
@@ -125,20 +231,20 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 			return
 		}
 		activeCapture = btn
-		heldMods = 0
+		capture.Reset()
 		btn.SetText("Press key combination…")
 
 		dc.SetOnKeyUp(func(ev *fyne.KeyEvent) {
 			n := strings.ToLower(string(ev.Name))
 			switch {
 			case strings.Contains(n, "control"):
-				heldMods &^= fyne.KeyModifierControl
+				capture.ModifierUp(core.ModControl)
 			case strings.Contains(n, "alt"):
-				heldMods &^= fyne.KeyModifierAlt
+				capture.ModifierUp(core.ModAlt)
 			case strings.Contains(n, "shift"):
-				heldMods &^= fyne.KeyModifierShift
+				capture.ModifierUp(core.ModShift)
 			case strings.Contains(n, "super") || strings.Contains(n, "meta"):
-				heldMods &^= fyne.KeyModifierSuper
+				capture.ModifierUp(core.ModSuper)
 			}
 		})
 
@@ -149,16 +255,16 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 			n := strings.ToLower(string(ev.Name))
 			switch {
 			case strings.Contains(n, "control"):
-				heldMods |= fyne.KeyModifierControl
+				capture.ModifierDown(core.ModControl)
 				return
 			case strings.Contains(n, "alt"):
-				heldMods |= fyne.KeyModifierAlt
+				capture.ModifierDown(core.ModAlt)
 				return
 			case strings.Contains(n, "shift"):
-				heldMods |= fyne.KeyModifierShift
+				capture.ModifierDown(core.ModShift)
 				return
 			case strings.Contains(n, "super") || strings.Contains(n, "meta"):
-				heldMods |= fyne.KeyModifierSuper
+				capture.ModifierDown(core.ModSuper)
 				return
 			case strings.Contains(n, "caps"):
 				return
@@ -174,28 +280,13 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 				return
 			}
 
-			// Require at least one of Alt/Ctrl/Super; Shift alone is not enough.
-			if heldMods&(fyne.KeyModifierAlt|fyne.KeyModifierControl|fyne.KeyModifierSuper) == 0 {
+			combo, ok := capture.Resolve(n)
+			if !ok {
 				return
 			}
 
-			var parts []string
-			if heldMods&fyne.KeyModifierControl != 0 {
-				parts = append(parts, "Ctrl")
-			}
-			if heldMods&fyne.KeyModifierAlt != 0 {
-				parts = append(parts, "Alt")
-			}
-			if heldMods&fyne.KeyModifierShift != 0 {
-				parts = append(parts, "Shift")
-			}
-			if heldMods&fyne.KeyModifierSuper != 0 {
-				parts = append(parts, "Super")
-			}
-			parts = append(parts, n)
-
 			stopCapture()
-			*target = strings.Join(parts, "-")
+			*target = combo
 			if displayFn != nil {
 				btn.SetText(displayFn(*target))
 			} else {
@@ -225,6 +316,16 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 		startCapture(undoHotkeyBtn, &currentUndoHotkey, nil, func() { updateSaveBtn() })
 	}
 
+	cycleProfileHotkeyBtn := widget.NewButton(pttBtnLabel(cfg.CycleProfileHotkey), nil)
+	cycleProfileHotkeyBtn.OnTapped = func() {
+		if activeCapture == cycleProfileHotkeyBtn {
+			stopCapture()
+			cycleProfileHotkeyBtn.SetText(pttBtnLabel(currentCycleProfileHotkey))
+			return
+		}
+		startCapture(cycleProfileHotkeyBtn, &currentCycleProfileHotkey, pttBtnLabel, func() { updateSaveBtn() })
+	}
+
 	pttHotkeyBtn := widget.NewButton(pttBtnLabel(cfg.PTTHotkey), nil)
 	pttClearBtn := widget.NewButton("Clear", func() {
 		stopCapture()
@@ -255,6 +356,198 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 	punctCheck := widget.NewCheck("Add punctuation", nil)
 	punctCheck.SetChecked(cfg.EnablePunctuation)
 
+	vocabEntry := widget.NewMultiLineEntry()
+	vocabEntry.SetPlaceHolder("One name/term per line, e.g.\nKubernetes\nAlijeyrad")
+	vocabEntry.SetText(vocabToLines(cfg.Vocabulary))
+	vocabEntry.Wrapping = fyne.TextWrapOff
+
+	initialBoost := 10.0
+	if len(cfg.Vocabulary) > 0 {
+		initialBoost = cfg.Vocabulary[0].Boost
+	}
+	vocabBoostLabel := widget.NewLabel(fmt.Sprintf("%.0f", initialBoost))
+	vocabBoostSlider := widget.NewSlider(0, 20)
+	vocabBoostSlider.SetValue(initialBoost)
+	vocabNote := widget.NewLabelWithStyle(
+		"Native on Google Cloud; fuzzy near-homophone replacement on every other backend",
+		fyne.TextAlignLeading, fyne.TextStyle{Italic: true},
+	)
+
+	// profileSelect switches between named presets of the recognition
+	// fields above (language, backend, sensitivity, silence threshold, API
+	// key, vocabulary, the Google Cloud options, and so on).
+	// Switching applies and persists immediately, like CycleProfileHotkey
+	// does at runtime, rather than waiting for the Save button.
+	profileSelect := widget.NewSelect(cfg.ProfileNames(), nil)
+	if cfg.ActiveProfile != "" {
+		profileSelect.SetSelected(cfg.ActiveProfile)
+	}
+
+	refreshProfileList := func() {
+		profileSelect.Options = cfg.ProfileNames()
+		profileSelect.Refresh()
+	}
+
+	applyProfileToWidgets := func() {
+		profileLabel := codeToLabel[cfg.Language]
+		if profileLabel == "" {
+			profileLabel = cfg.Language
+		}
+		langSelect.SetSelected(profileLabel)
+		apiKeyEntry.SetText(cfg.APIKey)
+		advancedCheck.SetChecked(cfg.UseAdvancedAPI)
+		profileBackend := codeToBackend[cfg.Backend]
+		if profileBackend == "" {
+			profileBackend = backendLabels[0]
+		}
+		backendSelect.SetSelected(profileBackend)
+		streamingCheck.SetChecked(cfg.Streaming)
+		modelSizeSelect.SetSelected(cfg.WhisperModelSize)
+		profileCloudModel := cfg.Model
+		if profileCloudModel == "" {
+			profileCloudModel = "(default)"
+		}
+		cloudModelSelect.SetSelected(profileCloudModel)
+		autoPunctCheck.SetChecked(cfg.EnableAutomaticPunctuation)
+		enhancedCheck.SetChecked(cfg.UseEnhanced)
+		profanityCheck.SetChecked(cfg.ProfanityFilter)
+		altLangEntry.SetText(strings.Join(cfg.AlternativeLanguageCodes, ", "))
+		maxAltEntry.SetText(strconv.Itoa(int(cfg.MaxAlternatives)))
+		wordTimingCheck.SetChecked(cfg.EnableWordTimeOffsets)
+		wordConfidenceCheck.SetChecked(cfg.EnableWordConfidence)
+		longFormCheck.SetChecked(cfg.LongForm)
+		gcsBucketEntry.SetText(cfg.GCSBucket)
+		silenceSlider.SetValue(float64(cfg.SilenceChunks))
+		sensitivitySlider.SetValue(cfg.Sensitivity)
+		timeoutEntry.SetText(strconv.Itoa(cfg.Timeout))
+		punctCheck.SetChecked(cfg.EnablePunctuation)
+		vocabEntry.SetText(vocabToLines(cfg.Vocabulary))
+		profileBoost := 10.0
+		if len(cfg.Vocabulary) > 0 {
+			profileBoost = cfg.Vocabulary[0].Boost
+		}
+		vocabBoostSlider.SetValue(profileBoost)
+	}
+
+	newProfileBtn := widget.NewButton("New…", func() {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetPlaceHolder("Profile name")
+		dialog.NewCustomConfirm("New profile", "Create", "Cancel", nameEntry, func(ok bool) {
+			if !ok || nameEntry.Text == "" {
+				return
+			}
+			cfg.SaveProfile(nameEntry.Text)
+			cfg.ActiveProfile = nameEntry.Text
+			onSave(cfg)
+			refreshProfileList()
+			profileSelect.SetSelected(nameEntry.Text)
+		}, w).Show()
+	})
+
+	duplicateProfileBtn := widget.NewButton("Duplicate", func() {
+		if profileSelect.Selected == "" {
+			return
+		}
+		nameEntry := widget.NewEntry()
+		nameEntry.SetPlaceHolder("New profile name")
+		dialog.NewCustomConfirm("Duplicate profile", "Create", "Cancel", nameEntry, func(ok bool) {
+			if !ok || nameEntry.Text == "" {
+				return
+			}
+			if err := cfg.DuplicateProfile(profileSelect.Selected, nameEntry.Text); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			onSave(cfg)
+			refreshProfileList()
+			profileSelect.SetSelected(nameEntry.Text)
+		}, w).Show()
+	})
+
+	deleteProfileBtn := widget.NewButton("Delete", func() {
+		if profileSelect.Selected == "" {
+			return
+		}
+		name := profileSelect.Selected
+		dialog.ShowConfirm("Delete profile", fmt.Sprintf("Delete %q?", name), func(ok bool) {
+			if !ok {
+				return
+			}
+			cfg.DeleteProfile(name)
+			onSave(cfg)
+			refreshProfileList()
+			profileSelect.ClearSelected()
+		}, w)
+	})
+
+	importProfileBtn := widget.NewButton("Import…", func() {
+		dialog.ShowFileOpen(func(rc fyne.URIReadCloser, err error) {
+			if err != nil || rc == nil {
+				return
+			}
+			defer rc.Close()
+			name := strings.TrimSuffix(rc.URI().Name(), filepath.Ext(rc.URI().Name()))
+			if err := cfg.ImportProfile(name, rc.URI().Path()); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			refreshProfileList()
+			profileSelect.SetSelected(name)
+		}, w)
+	})
+
+	exportProfileBtn := widget.NewButton("Export…", func() {
+		if profileSelect.Selected == "" {
+			return
+		}
+		dialog.ShowFileSave(func(wc fyne.URIWriteCloser, err error) {
+			if err != nil || wc == nil {
+				return
+			}
+			wc.Close()
+			if err := cfg.ExportProfile(profileSelect.Selected, wc.URI().Path()); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}, w)
+	})
+
+	profileSelect.OnChanged = func(name string) {
+		if name == "" || name == cfg.ActiveProfile {
+			return
+		}
+		if err := cfg.ApplyProfile(name); err != nil {
+			return
+		}
+		applyProfileToWidgets()
+		onSave(cfg)
+		updateSaveBtn()
+	}
+
+	soundCheck := widget.NewCheck("Play sound cues", nil)
+	soundCheck.SetChecked(cfg.SoundEnabled)
+
+	notifyCheck := widget.NewCheck("Show desktop notifications", nil)
+	notifyCheck.SetChecked(cfg.NotifyEnabled)
+
+	popupNotifyCheck := widget.NewCheck("Notify when popup isn't visible (D-Bus)", nil)
+	popupNotifyCheck.SetChecked(cfg.PopupNotify)
+
+	soundStartEntry := widget.NewEntry()
+	soundStartEntry.SetText(cfg.SoundStart)
+	soundStartEntry.SetPlaceHolder("Path to WAV/OGG, blank for none")
+
+	soundStopEntry := widget.NewEntry()
+	soundStopEntry.SetText(cfg.SoundStop)
+	soundStopEntry.SetPlaceHolder("Path to WAV/OGG, blank for none")
+
+	soundErrorEntry := widget.NewEntry()
+	soundErrorEntry.SetText(cfg.SoundError)
+	soundErrorEntry.SetPlaceHolder("Path to WAV/OGG, blank for none")
+
+	soundUndoEntry := widget.NewEntry()
+	soundUndoEntry.SetText(cfg.SoundUndo)
+	soundUndoEntry.SetPlaceHolder("Path to WAV/OGG, blank for none")
+
 	saveBtn := widget.NewButton("Save", nil)
 	saveBtn.Importance = widget.HighImportance
 	saveBtn.Disable()
@@ -268,21 +561,93 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 		return langSelect.Selected
 	}
 
-	hasChanges := func() bool {
+	currentBackend := func() string {
+		if code, ok := backendToCode[backendSelect.Selected]; ok {
+			return code
+		}
+		return config.BackendGoogleFree
+	}
+
+	currentCloudModel := func() string {
+		if cloudModelSelect.Selected == "(default)" {
+			return ""
+		}
+		return cloudModelSelect.Selected
+	}
+
+	currentAltLangs := func() []string {
+		var codes []string
+		for _, code := range strings.Split(altLangEntry.Text, ",") {
+			code = strings.TrimSpace(code)
+			if code != "" {
+				codes = append(codes, code)
+			}
+		}
+		return codes
+	}
+
+	// buildDraft assembles a *config.Config from the current widget state.
+	// Both hasChanges (via core.Changed) and doSave use it, so the settings
+	// form's notion of "what can be edited" lives in one place.
+	buildDraft := func() *config.Config {
 		timeout, err := strconv.Atoi(timeoutEntry.Text)
 		if err != nil || timeout < 5 {
 			timeout = cfg.Timeout
 		}
-		return currentLang() != cfg.Language ||
-			apiKeyEntry.Text != cfg.APIKey ||
-			advancedCheck.Checked != cfg.UseAdvancedAPI ||
-			int(silenceSlider.Value) != cfg.SilenceChunks ||
-			fmt.Sprintf("%.1f", sensitivitySlider.Value) != fmt.Sprintf("%.1f", cfg.Sensitivity) ||
-			currentHotkey != cfg.Hotkey ||
-			currentUndoHotkey != cfg.UndoHotkey ||
-			currentPTTHotkey != cfg.PTTHotkey ||
-			timeout != cfg.Timeout ||
-			punctCheck.Checked != cfg.EnablePunctuation
+		// Round-trip sensitivity through the same precision the slider
+		// displays at, so a tiny float representation difference from
+		// SetValue doesn't register as a change.
+		sensitivity, _ := strconv.ParseFloat(fmt.Sprintf("%.1f", sensitivitySlider.Value), 64)
+		maxAlternatives, err := strconv.Atoi(maxAltEntry.Text)
+		if err != nil || maxAlternatives < 0 {
+			maxAlternatives = int(cfg.MaxAlternatives)
+		}
+		return &config.Config{
+			Hotkey:             currentHotkey,
+			UndoHotkey:         currentUndoHotkey,
+			PTTHotkey:          currentPTTHotkey,
+			CycleProfileHotkey: currentCycleProfileHotkey,
+			Profiles:           cfg.Profiles,
+			ActiveProfile:      cfg.ActiveProfile,
+			Language:           currentLang(),
+			Timeout:            timeout,
+			SilenceChunks:      int(silenceSlider.Value),
+			Sensitivity:        sensitivity,
+			APIKey:             apiKeyEntry.Text,
+			UseAdvancedAPI:     advancedCheck.Checked,
+			Backend:            currentBackend(),
+			Streaming:          streamingCheck.Checked,
+			WhisperModelPath:   cfg.WhisperModelPath,
+			WhisperModelSize:   modelSizeSelect.Selected,
+			WhisperThreads:     cfg.WhisperThreads,
+			VoskModelPath:      cfg.VoskModelPath,
+			VoskModelName:      cfg.VoskModelName,
+			Vocabulary:         linesToVocab(vocabEntry.Text, vocabBoostSlider.Value),
+			EnablePunctuation:  punctCheck.Checked,
+
+			EnableAutomaticPunctuation: autoPunctCheck.Checked,
+			AlternativeLanguageCodes:   currentAltLangs(),
+			Model:                      currentCloudModel(),
+			UseEnhanced:                enhancedCheck.Checked,
+			ProfanityFilter:            profanityCheck.Checked,
+			MaxAlternatives:            int32(maxAlternatives),
+			EnableWordTimeOffsets:      wordTimingCheck.Checked,
+			EnableWordConfidence:       wordConfidenceCheck.Checked,
+			LongForm:                   longFormCheck.Checked,
+			GCSBucket:                  gcsBucketEntry.Text,
+
+			SoundEnabled:  soundCheck.Checked,
+			NotifyEnabled: notifyCheck.Checked,
+			PopupNotify:   popupNotifyCheck.Checked,
+			SoundStart:    soundStartEntry.Text,
+			SoundStop:     soundStopEntry.Text,
+			SoundError:    soundErrorEntry.Text,
+			SoundUndo:     soundUndoEntry.Text,
+		}
+	}
+
+	hasChanges := func() bool {
+		return core.Changed(cfg, buildDraft())
 	}
 
 	updateSaveBtn = func() {
@@ -296,6 +661,33 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 	langSelect.OnChanged = func(_ string) { updateSaveBtn() }
 	apiKeyEntry.OnChanged = func(_ string) { updateSaveBtn() }
 	advancedCheck.OnChanged = func(_ bool) { updateSaveBtn() }
+	backendSelect.OnChanged = func(sel string) {
+		if sel == "Whisper (offline)" {
+			modelSizeSelect.Enable()
+		} else {
+			modelSizeSelect.Disable()
+		}
+		if sel == "Google Cloud (online)" {
+			streamingCheck.Enable()
+			enableCloudOnlyWidgets()
+		} else {
+			streamingCheck.Disable()
+			disableCloudOnlyWidgets()
+		}
+		updateSaveBtn()
+	}
+	modelSizeSelect.OnChanged = func(_ string) { updateSaveBtn() }
+	streamingCheck.OnChanged = func(_ bool) { updateSaveBtn() }
+	cloudModelSelect.OnChanged = func(_ string) { updateSaveBtn() }
+	autoPunctCheck.OnChanged = func(_ bool) { updateSaveBtn() }
+	enhancedCheck.OnChanged = func(_ bool) { updateSaveBtn() }
+	profanityCheck.OnChanged = func(_ bool) { updateSaveBtn() }
+	altLangEntry.OnChanged = func(_ string) { updateSaveBtn() }
+	maxAltEntry.OnChanged = func(_ string) { updateSaveBtn() }
+	wordTimingCheck.OnChanged = func(_ bool) { updateSaveBtn() }
+	wordConfidenceCheck.OnChanged = func(_ bool) { updateSaveBtn() }
+	longFormCheck.OnChanged = func(_ bool) { updateSaveBtn() }
+	gcsBucketEntry.OnChanged = func(_ string) { updateSaveBtn() }
 	silenceSlider.OnChanged = func(v float64) {
 		silenceLabel.SetText(fmt.Sprintf("%.0f chunks (~%.0f ms)", v, v*62))
 		updateSaveBtn()
@@ -306,24 +698,21 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 	}
 	timeoutEntry.OnChanged = func(_ string) { updateSaveBtn() }
 	punctCheck.OnChanged = func(_ bool) { updateSaveBtn() }
+	vocabEntry.OnChanged = func(_ string) { updateSaveBtn() }
+	vocabBoostSlider.OnChanged = func(v float64) {
+		vocabBoostLabel.SetText(fmt.Sprintf("%.0f", v))
+		updateSaveBtn()
+	}
+	soundCheck.OnChanged = func(_ bool) { updateSaveBtn() }
+	notifyCheck.OnChanged = func(_ bool) { updateSaveBtn() }
+	popupNotifyCheck.OnChanged = func(_ bool) { updateSaveBtn() }
+	soundStartEntry.OnChanged = func(_ string) { updateSaveBtn() }
+	soundStopEntry.OnChanged = func(_ string) { updateSaveBtn() }
+	soundErrorEntry.OnChanged = func(_ string) { updateSaveBtn() }
+	soundUndoEntry.OnChanged = func(_ string) { updateSaveBtn() }
 
 	doSave := func() {
-		timeout, err := strconv.Atoi(timeoutEntry.Text)
-		if err != nil || timeout < 5 {
-			timeout = cfg.Timeout
-		}
-		newCfg := &config.Config{
-			Hotkey:            currentHotkey,
-			UndoHotkey:        currentUndoHotkey,
-			PTTHotkey:         currentPTTHotkey,
-			Language:          currentLang(),
-			Timeout:           timeout,
-			SilenceChunks:     int(silenceSlider.Value),
-			Sensitivity:       sensitivitySlider.Value,
-			APIKey:            apiKeyEntry.Text,
-			UseAdvancedAPI:    advancedCheck.Checked,
-			EnablePunctuation: punctCheck.Checked,
-		}
+		newCfg := buildDraft()
 		onSave(newCfg)
 		*cfg = *newCfg
 		saveBtn.Disable()
@@ -361,9 +750,32 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 	)
 
 	form := container.New(layout.NewFormLayout(),
+		widget.NewLabelWithStyle("Profile", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil,
+			container.NewHBox(newProfileBtn, duplicateProfileBtn, deleteProfileBtn, importProfileBtn, exportProfileBtn),
+			profileSelect),
+
+		widget.NewLabelWithStyle("Cycle profile hotkey", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		cycleProfileHotkeyBtn,
+
+		widget.NewSeparator(), widget.NewSeparator(),
+
 		widget.NewLabelWithStyle("Language", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
 		langSelect,
 
+		widget.NewLabelWithStyle("Recognition backend", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		backendSelect,
+
+		widget.NewLabel(""),
+		streamingCheck,
+		widget.NewLabel(""),
+		streamingNote,
+
+		widget.NewLabelWithStyle("Whisper model", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		modelSizeSelect,
+		widget.NewLabel(""),
+		modelSizeNote,
+
 		widget.NewLabelWithStyle("Custom API key", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
 		apiKeyEntry,
 
@@ -399,6 +811,64 @@ func showSettingsWindow(fyneApp fyne.App, cfg *config.Config, onSave func(*confi
 
 		widget.NewLabel(""),
 		punctCheck,
+
+		widget.NewSeparator(), widget.NewSeparator(),
+
+		widget.NewLabelWithStyle("Cloud model", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		cloudModelSelect,
+
+		widget.NewLabel(""),
+		autoPunctCheck,
+		widget.NewLabel(""),
+		enhancedCheck,
+		widget.NewLabel(""),
+		profanityCheck,
+		widget.NewLabel(""),
+		cloudNote,
+
+		widget.NewLabelWithStyle("Alternative languages", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		altLangEntry,
+
+		widget.NewLabelWithStyle("Max alternatives", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		maxAltEntry,
+
+		widget.NewLabel(""),
+		wordTimingCheck,
+		widget.NewLabel(""),
+		wordConfidenceCheck,
+		widget.NewLabel(""),
+		longFormCheck,
+
+		widget.NewLabelWithStyle("GCS bucket", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		gcsBucketEntry,
+
+		widget.NewSeparator(), widget.NewSeparator(),
+
+		widget.NewLabelWithStyle("Custom vocabulary", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		vocabEntry,
+
+		widget.NewLabelWithStyle("Vocabulary boost", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		container.NewBorder(nil, nil, nil, vocabBoostLabel, vocabBoostSlider),
+		widget.NewLabel(""),
+		vocabNote,
+
+		widget.NewSeparator(), widget.NewSeparator(),
+
+		widget.NewLabel(""),
+		soundCheck,
+		widget.NewLabel(""),
+		notifyCheck,
+		widget.NewLabel(""),
+		popupNotifyCheck,
+
+		widget.NewLabelWithStyle("Start sound", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		soundStartEntry,
+		widget.NewLabelWithStyle("Stop sound", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		soundStopEntry,
+		widget.NewLabelWithStyle("Error sound", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		soundErrorEntry,
+		widget.NewLabelWithStyle("Undo sound", fyne.TextAlignTrailing, fyne.TextStyle{Bold: true}),
+		soundUndoEntry,
 	)
 
 	versionLabel := widget.NewLabelWithStyle(
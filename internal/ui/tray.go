@@ -16,10 +16,12 @@ import (
 //go:embed assets/icon.png
 var iconPNG []byte
 
-// Tray manages the system tray icon (via Fyne) and the dictation popup (via X11).
+// Tray manages the system tray icon (via Fyne) and the dictation popup
+// (Wayland layer-shell or X11, via popupBackend).
 type Tray struct {
 	fyneApp  fyne.App
-	popup    *X11Popup
+	popup    popupBackend
+	notifier Notifier
 	dictItem *fyne.MenuItem
 
 	cfgMu sync.RWMutex
@@ -36,11 +38,12 @@ func (t *Tray) Run(cfg *config.Config, onDictate func(), onQuit func(), startupE
 	t.cfg = cfg
 	t.cfgMu.Unlock()
 
-	popup, err := newX11Popup()
+	popup, err := newPopup()
 	if err != nil {
-		log.Printf("warning: X11 popup unavailable (%v); animations disabled", err)
+		log.Printf("warning: dictation popup unavailable (%v); animations disabled", err)
 	}
 	t.popup = popup
+	t.applyNotifierConfig(cfg)
 
 	a := app.NewWithID("com.alijeyrad.gotalk-dictation")
 	t.fyneApp = a
@@ -86,12 +89,28 @@ func (t *Tray) Run(cfg *config.Config, onDictate func(), onQuit func(), startupE
 	a.Run()
 }
 
+// SetOnSettingsSave sets the callback invoked when the user saves settings,
+// equivalent to assigning the OnSettingsSave field directly. It exists so
+// main can hold a Tray or a tui.Console behind a single frontend interface.
+func (t *Tray) SetOnSettingsSave(f func(*config.Config)) { t.OnSettingsSave = f }
+
 // UpdateConfig stores the latest config so the settings window always opens
 // with current values. Must be called after every settings save.
 func (t *Tray) UpdateConfig(cfg *config.Config) {
 	t.cfgMu.Lock()
 	t.cfg = cfg
 	t.cfgMu.Unlock()
+	t.applyNotifierConfig(cfg)
+}
+
+// applyNotifierConfig enables or disables the D-Bus popup notifier to match
+// cfg.PopupNotify, reusing cfg.SoundEnabled for its optional error sound cue.
+func (t *Tray) applyNotifierConfig(cfg *config.Config) {
+	if !cfg.PopupNotify {
+		t.notifier = nil
+		return
+	}
+	t.notifier = &DBusNotifier{PlaySound: cfg.SoundEnabled}
 }
 
 // ---- State methods ---------------------------------------------------------
@@ -110,11 +129,24 @@ func (t *Tray) SetProcessing() {
 	}
 }
 
+// SetPartial renders an evolving partial transcript inside the popup, so
+// users see words appear as they speak instead of waiting for Done. Safe to
+// call repeatedly with successive interim hypotheses; has no visible effect
+// on backends (WaylandPopup) that can't render text.
+func (t *Tray) SetPartial(text string) {
+	if t.popup != nil {
+		t.popup.SetPartial(text)
+	}
+}
+
 // SetDone flashes green for 2 seconds then hides.
 func (t *Tray) SetDone(text string) {
 	if t.popup != nil {
 		t.popup.SetState(stDone)
 	}
+	if t.notifier != nil {
+		t.notifier.Done(text)
+	}
 	go func() {
 		time.Sleep(2 * time.Second)
 		t.SetIdle()
@@ -133,6 +165,9 @@ func (t *Tray) SetError(msg string) {
 	if t.popup != nil {
 		t.popup.Show(stError)
 	}
+	if t.notifier != nil {
+		t.notifier.Error(msg)
+	}
 	go func() {
 		time.Sleep(3 * time.Second)
 		t.SetIdle()
@@ -0,0 +1,409 @@
+// Package tui implements a tcell-based terminal frontend for
+// gotalk-dictation: a settings form, a live VU meter, and start/stop
+// controls, for servers, SSH sessions, and tty-only Wayland sessions where
+// Fyne's canvas key capture is flaky (see the KDE Wayland comment on
+// ui.startCapture). Console mirrors ui.Tray's public surface so main can
+// choose either frontend behind the same calls.
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	tcell "github.com/gdamore/tcell/v2"
+
+	"github.com/Alijeyrad/gotalk-dictation/internal/config"
+	"github.com/Alijeyrad/gotalk-dictation/internal/ui/core"
+)
+
+var (
+	styleNormal  = tcell.StyleDefault
+	styleHeader  = tcell.StyleDefault.Bold(true)
+	styleListen  = tcell.StyleDefault.Foreground(tcell.ColorRed)
+	styleProc    = tcell.StyleDefault.Foreground(tcell.ColorBlue)
+	styleDone    = tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	styleErr     = tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true)
+	styleCapture = tcell.StyleDefault.Foreground(tcell.ColorYellow)
+)
+
+// Console is the tcell-based equivalent of ui.Tray. A nil screen (before Run
+// is called) makes every drawing method a no-op, matching the nil-safety
+// ui.Tray gets for free from only touching its popup when non-nil.
+type Console struct {
+	mu     sync.Mutex
+	screen tcell.Screen
+
+	cfgMu sync.RWMutex
+	cfg   *config.Config
+
+	state   string // "Idle", "Listening", "Processing", "Done", "Error"
+	msg     string
+	partial string  // evolving partial transcript from streaming recognition
+	level   float64 // last normalized RMS, 0..1, for the VU meter
+
+	// OnSettingsSave is called when the user saves the settings form,
+	// mirroring ui.Tray.OnSettingsSave.
+	OnSettingsSave func(*config.Config)
+}
+
+// SetOnSettingsSave sets the callback invoked when the settings form is
+// saved with Ctrl-S, equivalent to assigning the OnSettingsSave field
+// directly. It exists so main can hold a Console or a ui.Tray behind a
+// single frontend interface.
+func (c *Console) SetOnSettingsSave(f func(*config.Config)) { c.OnSettingsSave = f }
+
+// NewLevelWriter returns an io.Writer that, fed the newline-delimited JSON
+// event stream from internal/events, updates the console's VU meter and
+// status line. Wire it as (part of) a speech.Recognizer's Events target when
+// running under --tui, so the meter reflects real microphone input without
+// the TUI needing to read audio chunks directly.
+func (c *Console) NewLevelWriter() io.Writer { return &levelWriter{c: c} }
+
+type levelWriter struct {
+	c   *Console
+	buf []byte
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := strings.IndexByte(string(w.buf), '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buf[:i]
+		w.buf = w.buf[i+1:]
+		w.c.handleEventLine(line)
+	}
+	return len(p), nil
+}
+
+func (c *Console) handleEventLine(line []byte) {
+	// Minimal ndjson sniffing: only "chunk" events carry an rms field the
+	// meter cares about; everything else is already reflected via the
+	// SetXxx calls main wires to the same lifecycle points as ui.Tray.
+	s := string(line)
+	if !strings.Contains(s, `"type":"chunk"`) {
+		return
+	}
+	idx := strings.Index(s, `"rms":`)
+	if idx < 0 {
+		return
+	}
+	var rms float64
+	fmt.Sscanf(s[idx+len(`"rms":`):], "%f", &rms) //nolint:errcheck
+	const maxRMS = 8000                           // empirically loud speech on a headset mic
+	level := rms / maxRMS
+	if level > 1 {
+		level = 1
+	}
+	c.mu.Lock()
+	c.level = level
+	c.mu.Unlock()
+	c.draw()
+}
+
+// Run initializes the terminal screen and runs the input loop. Blocks until
+// the user quits. Must be called on the main goroutine, like ui.Tray.Run.
+func (c *Console) Run(cfg *config.Config, onDictate func(), onQuit func(), startupErr error) {
+	c.cfgMu.Lock()
+	c.cfg = cfg
+	c.cfgMu.Unlock()
+	c.state = "Idle"
+	if startupErr != nil {
+		c.state = "Error"
+		c.msg = startupErr.Error()
+	}
+
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		fmt.Println("tui: creating screen:", err)
+		return
+	}
+	if err := screen.Init(); err != nil {
+		fmt.Println("tui: initializing screen:", err)
+		return
+	}
+	c.mu.Lock()
+	c.screen = screen
+	c.mu.Unlock()
+	defer screen.Fini()
+
+	c.draw()
+
+	for {
+		ev := screen.PollEvent()
+		switch ev := ev.(type) {
+		case *tcell.EventResize:
+			screen.Sync()
+			c.draw()
+		case *tcell.EventKey:
+			switch {
+			case ev.Key() == tcell.KeyCtrlC, ev.Rune() == 'q':
+				onQuit()
+				return
+			case ev.Rune() == 'd' || ev.Key() == tcell.KeyEnter:
+				go onDictate()
+			case ev.Rune() == 's':
+				c.runSettingsForm()
+			}
+		}
+	}
+}
+
+// UpdateConfig stores the latest config, mirroring ui.Tray.UpdateConfig.
+func (c *Console) UpdateConfig(cfg *config.Config) {
+	c.cfgMu.Lock()
+	c.cfg = cfg
+	c.cfgMu.Unlock()
+	c.draw()
+}
+
+// ---- State methods, mirroring ui.Tray's ------------------------------------
+
+func (c *Console) SetListening()       { c.setState("Listening", "") }
+func (c *Console) SetProcessing()      { c.setState("Processing", "") }
+func (c *Console) SetDone(text string) { c.setState("Done", text) }
+func (c *Console) SetIdle()            { c.setState("Idle", "") }
+func (c *Console) SetError(msg string) { c.setState("Error", msg) }
+
+func (c *Console) setState(state, msg string) {
+	c.mu.Lock()
+	c.state = state
+	c.msg = msg
+	c.partial = ""
+	c.mu.Unlock()
+	c.draw()
+}
+
+// SetPartial renders an evolving partial transcript from streaming
+// recognition below the status line, so users see words appear as they
+// speak instead of waiting for Done.
+func (c *Console) SetPartial(text string) {
+	c.mu.Lock()
+	c.partial = text
+	c.mu.Unlock()
+	c.draw()
+}
+
+// ---- Rendering --------------------------------------------------------------
+
+func (c *Console) draw() {
+	c.mu.Lock()
+	screen := c.screen
+	state := c.state
+	msg := c.msg
+	partial := c.partial
+	level := c.level
+	c.mu.Unlock()
+	if screen == nil {
+		return
+	}
+
+	c.cfgMu.RLock()
+	cfg := c.cfg
+	c.cfgMu.RUnlock()
+
+	screen.Clear()
+	drawText(screen, 0, 0, styleHeader, "GoTalk Dictation — terminal console")
+	drawText(screen, 0, 1, styleNormal, strings.Repeat("─", 60))
+
+	stateStyle := styleNormal
+	switch state {
+	case "Listening":
+		stateStyle = styleListen
+	case "Processing":
+		stateStyle = styleProc
+	case "Done":
+		stateStyle = styleDone
+	case "Error":
+		stateStyle = styleErr
+	}
+	drawText(screen, 0, 3, styleNormal, "Status: ")
+	drawText(screen, 8, 3, stateStyle, state)
+	if msg != "" {
+		drawText(screen, 0, 4, styleNormal, msg)
+	}
+	if partial != "" {
+		drawText(screen, 0, 5, styleListen, "Partial: "+partial)
+	}
+
+	drawText(screen, 0, 6, styleNormal, "Level:  ["+vuBar(level, 30)+"]")
+
+	if cfg != nil {
+		drawText(screen, 0, 8, styleNormal, fmt.Sprintf("Hotkey: %s   PTT: %s   Undo: %s",
+			cfg.Hotkey, cfg.PTTHotkey, cfg.UndoHotkey))
+		drawText(screen, 0, 9, styleNormal, fmt.Sprintf("Language: %s   Backend: %s", cfg.Language, cfg.Backend))
+	}
+
+	drawText(screen, 0, 11, styleNormal, "[d] toggle dictation   [s] settings   [q] quit")
+
+	screen.Show()
+}
+
+func vuBar(level float64, width int) string {
+	filled := int(level * float64(width))
+	if filled > width {
+		filled = width
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	return strings.Repeat("█", filled) + strings.Repeat(" ", width-filled)
+}
+
+func drawText(screen tcell.Screen, x, y int, style tcell.Style, text string) {
+	for i, r := range text {
+		screen.SetContent(x+i, y, r, nil, style)
+	}
+}
+
+// ---- Settings form ----------------------------------------------------------
+
+// runSettingsForm blocks the input loop and lets the user edit config fields
+// with arrow keys / enter, and assign hotkeys by pressing a raw key
+// combination (handled by the same core.HotkeyCapture state machine the
+// Fyne settings window drives).
+func (c *Console) runSettingsForm() {
+	c.mu.Lock()
+	screen := c.screen
+	c.mu.Unlock()
+	if screen == nil {
+		return
+	}
+
+	c.cfgMu.RLock()
+	draft := *c.cfg
+	orig := *c.cfg
+	c.cfgMu.RUnlock()
+
+	fields := []string{"Language", "Hotkey", "PTT Hotkey", "Undo Hotkey", "Timeout", "Punctuation"}
+	selected := 0
+	capturingHotkey := -1 // index into fields currently capturing a combo, or -1
+	var capture core.HotkeyCapture
+
+	render := func() {
+		screen.Clear()
+		drawText(screen, 0, 0, styleHeader, "Settings — ↑/↓ select, Enter edit, Esc/s close, Ctrl-S save")
+		for i, f := range fields {
+			style := styleNormal
+			if i == selected {
+				style = style.Reverse(true)
+			}
+			var val string
+			switch f {
+			case "Language":
+				val = draft.Language
+			case "Hotkey":
+				val = draft.Hotkey
+			case "PTT Hotkey":
+				val = draft.PTTHotkey
+			case "Undo Hotkey":
+				val = draft.UndoHotkey
+			case "Timeout":
+				val = fmt.Sprintf("%d", draft.Timeout)
+			case "Punctuation":
+				val = fmt.Sprintf("%v", draft.EnablePunctuation)
+			}
+			if capturingHotkey == i {
+				val = "press key combination…"
+				style = styleCapture
+			}
+			drawText(screen, 0, 2+i, style, fmt.Sprintf("%-14s %s", f+":", val))
+		}
+		if core.Changed(&orig, &draft) {
+			drawText(screen, 0, 2+len(fields)+1, styleDone, "* unsaved changes")
+		}
+		screen.Show()
+	}
+
+	render()
+	for {
+		ev := screen.PollEvent()
+		key, ok := ev.(*tcell.EventKey)
+		if !ok {
+			continue
+		}
+
+		if capturingHotkey >= 0 {
+			if key.Key() == tcell.KeyEscape {
+				capturingHotkey = -1
+				capture.Reset()
+				render()
+				continue
+			}
+			switch key.Key() {
+			case tcell.KeyCtrlSpace: // unused, placeholder to keep switch non-empty
+			}
+			if mod := key.Modifiers(); mod != 0 {
+				// tcell reports modifiers alongside the final key, not as
+				// separate down/up events, so resolve immediately.
+				var m core.ModMask
+				if mod&tcell.ModAlt != 0 {
+					m |= core.ModAlt
+				}
+				if mod&tcell.ModCtrl != 0 {
+					m |= core.ModControl
+				}
+				if mod&tcell.ModShift != 0 {
+					m |= core.ModShift
+				}
+				if mod&tcell.ModMeta != 0 {
+					m |= core.ModSuper
+				}
+				capture.Reset()
+				capture.ModifierDown(m)
+				name := strings.ToLower(tcell.KeyNames[key.Key()])
+				if key.Rune() != 0 {
+					name = strings.ToLower(string(key.Rune()))
+				}
+				if combo, ok := capture.Resolve(name); ok {
+					switch fields[capturingHotkey] {
+					case "Hotkey":
+						draft.Hotkey = combo
+					case "PTT Hotkey":
+						draft.PTTHotkey = combo
+					case "Undo Hotkey":
+						draft.UndoHotkey = combo
+					}
+					capturingHotkey = -1
+				}
+			}
+			render()
+			continue
+		}
+
+		switch {
+		case key.Key() == tcell.KeyEscape || key.Rune() == 's':
+			return
+		case key.Key() == tcell.KeyUp:
+			if selected > 0 {
+				selected--
+			}
+		case key.Key() == tcell.KeyDown:
+			if selected < len(fields)-1 {
+				selected++
+			}
+		case key.Key() == tcell.KeyCtrlS:
+			if c.OnSettingsSave != nil {
+				saved := draft
+				c.OnSettingsSave(&saved)
+			}
+			c.cfgMu.Lock()
+			c.cfg = &draft
+			c.cfgMu.Unlock()
+			return
+		case key.Key() == tcell.KeyEnter:
+			switch fields[selected] {
+			case "Hotkey", "PTT Hotkey", "Undo Hotkey":
+				capturingHotkey = selected
+				capture.Reset()
+			case "Punctuation":
+				draft.EnablePunctuation = !draft.EnablePunctuation
+			}
+		}
+		render()
+	}
+}
@@ -0,0 +1,380 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	wl "github.com/rajveermalviya/go-wayland/wayland/client"
+	layershell "github.com/rajveermalviya/go-wayland/wayland/unstable/wlr-layer-shell-unstable-v1"
+	xdgoutput "github.com/rajveermalviya/go-wayland/wayland/unstable/xdg-output-unstable-v1"
+	"golang.org/x/sys/unix"
+)
+
+// popupBackend is the Show/SetState/SetPartial/ShowDone/Hide/Close surface both
+// X11Popup and WaylandPopup implement. Tray holds one of these rather than
+// a concrete type so newPopup can pick whichever fits the session.
+type popupBackend interface {
+	Show(popState)
+	SetState(popState)
+	SetPartial(string)
+	ShowDone(string)
+	Hide()
+	Close()
+}
+
+// newPopup picks WaylandPopup under a Wayland session ($WAYLAND_DISPLAY
+// set) and falls back to X11Popup (XWayland or Xorg) otherwise, or if the
+// Wayland backend fails to bind the globals it needs.
+func newPopup() (popupBackend, error) {
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		if p, err := newWaylandPopup(); err == nil {
+			return p, nil
+		}
+	}
+	x, err := newX11Popup()
+	if err != nil {
+		return nil, err // a typed-nil *X11Popup here would satisfy t.popup != nil checks wrongly
+	}
+	return x, nil
+}
+
+// WaylandPopup is the wlr-layer-shell sibling of X11Popup: same
+// caret-relative overlay and pulsing-dot/spinning-arc animation, but drawn
+// into an SHM buffer with a small software rasterizer instead of X11
+// drawing requests, since Wayland clients own their own pixels. It has no
+// font, so unlike X11Popup it always shows the plain done/error dot rather
+// than a text preview.
+type WaylandPopup struct {
+	mu     sync.Mutex
+	state  popState
+	stopCh chan struct{}
+
+	display    *wl.Display
+	compositor *wl.Compositor
+	shm        *wl.Shm
+	layerShell *layershell.ZwlrLayerShellV1
+	xdgOutMgr  *xdgoutput.ZxdgOutputManagerV1
+	surface    *wl.Surface
+	layerSurf  *layershell.ZwlrLayerSurfaceV1
+	output     *wl.Output
+
+	logMu       sync.Mutex
+	logX, logY  int32
+	logW, logH  int32
+	haveLogical bool
+
+	w, h   int32
+	pool   *wl.ShmPool
+	buffer *wl.Buffer
+	mem    []byte // mmap'd SHM backing store, ARGB8888
+
+	configured bool
+}
+
+func newWaylandPopup() (*WaylandPopup, error) {
+	display, err := wl.Connect("")
+	if err != nil {
+		return nil, fmt.Errorf("wayland: %w", err)
+	}
+
+	p := &WaylandPopup{display: display, state: stHidden, stopCh: make(chan struct{})}
+
+	registry, err := display.GetRegistry()
+	if err != nil {
+		display.Context().Close()
+		return nil, fmt.Errorf("wayland: get_registry: %w", err)
+	}
+	registry.SetGlobalHandler(func(ev wl.RegistryGlobalEvent) {
+		switch ev.Interface {
+		case "wl_compositor":
+			p.compositor, _ = wl.BindCompositor(registry, ev.Name, ev.Version)
+		case "wl_shm":
+			p.shm, _ = wl.BindShm(registry, ev.Name, ev.Version)
+		case "wl_output":
+			if p.output == nil {
+				p.output, _ = wl.BindOutput(registry, ev.Name, ev.Version)
+			}
+		case "zwlr_layer_shell_v1":
+			p.layerShell, _ = layershell.BindZwlrLayerShellV1(registry, ev.Name, ev.Version)
+		case "zxdg_output_manager_v1":
+			p.xdgOutMgr, _ = xdgoutput.BindZxdgOutputManagerV1(registry, ev.Name, ev.Version)
+		}
+	})
+
+	if err := display.Context().RoundTrip(); err != nil { // let the registry enumerate globals
+		display.Context().Close()
+		return nil, fmt.Errorf("wayland: roundtrip: %w", err)
+	}
+	if p.compositor == nil || p.shm == nil || p.layerShell == nil {
+		display.Context().Close()
+		return nil, fmt.Errorf("wayland: compositor does not support zwlr_layer_shell_v1")
+	}
+
+	if err := p.init(); err != nil {
+		display.Context().Close()
+		return nil, err
+	}
+	go p.eventLoop()
+	go p.renderLoop()
+	return p, nil
+}
+
+func (p *WaylandPopup) init() error {
+	surface, err := p.compositor.CreateSurface()
+	if err != nil {
+		return fmt.Errorf("create_surface: %w", err)
+	}
+	p.surface = surface
+
+	layerSurf, err := p.layerShell.GetLayerSurface(surface, p.output, layershell.ZwlrLayerShellV1LayerOverlay, "gotalk-dictation")
+	if err != nil {
+		return fmt.Errorf("get_layer_surface: %w", err)
+	}
+	p.layerSurf = layerSurf
+
+	// Anchored top-left with a margin: wlr-layer-shell has no free-form x/y
+	// placement like an X11 override-redirect window, so caret-relative
+	// placement is done by pushing the anchored corner out via SetMargin
+	// (see Show).
+	layerSurf.SetAnchor(uint32(layershell.ZwlrLayerSurfaceV1AnchorTop | layershell.ZwlrLayerSurfaceV1AnchorLeft))
+	layerSurf.SetSize(popSz, popSz)
+	layerSurf.SetKeyboardInteractivity(0)
+	layerSurf.SetExclusiveZone(-1)
+
+	layerSurf.SetConfigureHandler(func(ev layershell.ZwlrLayerSurfaceV1ConfigureEvent) {
+		layerSurf.AckConfigure(ev.Serial)
+		p.mu.Lock()
+		p.configured = true
+		p.mu.Unlock()
+		p.commit()
+	})
+	layerSurf.SetClosedHandler(func(layershell.ZwlrLayerSurfaceV1ClosedEvent) {
+		p.Close()
+	})
+
+	if p.xdgOutMgr != nil && p.output != nil {
+		if xdgOut, err := p.xdgOutMgr.GetXdgOutput(p.output); err == nil {
+			xdgOut.SetLogicalPositionHandler(func(ev xdgoutput.ZxdgOutputV1LogicalPositionEvent) {
+				p.logMu.Lock()
+				p.logX, p.logY = ev.X, ev.Y
+				p.haveLogical = true
+				p.logMu.Unlock()
+			})
+			xdgOut.SetLogicalSizeHandler(func(ev xdgoutput.ZxdgOutputV1LogicalSizeEvent) {
+				p.logMu.Lock()
+				p.logW, p.logH = ev.Width, ev.Height
+				p.haveLogical = true
+				p.logMu.Unlock()
+			})
+		}
+	}
+
+	surface.Commit()
+	return nil
+}
+
+// allocBuffer (re)creates the SHM pool/buffer for a w x h ARGB8888 surface,
+// releasing any previous one.
+func (p *WaylandPopup) allocBuffer(w, h int32) error {
+	stride := w * 4
+	size := int(stride * h)
+
+	fd, err := unix.MemfdCreate("gotalk-popup", 0)
+	if err != nil {
+		return fmt.Errorf("memfd_create: %w", err)
+	}
+	defer unix.Close(fd)
+	if err := unix.Ftruncate(fd, int64(size)); err != nil {
+		return fmt.Errorf("ftruncate: %w", err)
+	}
+	mem, err := unix.Mmap(fd, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+	if err != nil {
+		return fmt.Errorf("mmap: %w", err)
+	}
+
+	pool, err := p.shm.CreatePool(fd, int32(size))
+	if err != nil {
+		unix.Munmap(mem)
+		return fmt.Errorf("create_pool: %w", err)
+	}
+	buffer, err := pool.CreateBuffer(0, w, h, stride, wl.ShmFormatArgb8888)
+	if err != nil {
+		pool.Destroy()
+		unix.Munmap(mem)
+		return fmt.Errorf("create_buffer: %w", err)
+	}
+
+	if p.buffer != nil {
+		p.buffer.Destroy()
+	}
+	if p.pool != nil {
+		p.pool.Destroy()
+	}
+	if p.mem != nil {
+		unix.Munmap(p.mem)
+	}
+	p.pool, p.buffer, p.mem, p.w, p.h = pool, buffer, mem, w, h
+	return nil
+}
+
+// Show positions the popup near the caret (best-effort: AT-SPI, else the
+// output center) and reveals it in state s.
+func (p *WaylandPopup) Show(s popState) {
+	p.mu.Lock()
+	p.state = s
+	p.mu.Unlock()
+
+	cx, cy := p.queryCaretPos()
+	left := cx - popSz/2
+	top := cy - popSz - 10
+	if left < 0 {
+		left = 0
+	}
+	if top < 0 {
+		top = 0
+	}
+
+	p.layerSurf.SetSize(popSz, popSz)
+	p.layerSurf.SetMargin(top, 0, 0, left)
+	p.surface.Commit()
+}
+
+func (p *WaylandPopup) SetState(s popState) {
+	p.mu.Lock()
+	p.state = s
+	p.mu.Unlock()
+}
+
+// ShowDone switches to the Done state. WaylandPopup has no font to render a
+// text preview with, so it always shows the green flash dot and ignores
+// text (the parameter is kept for interface parity with X11Popup.ShowDone).
+func (p *WaylandPopup) ShowDone(string) {
+	p.SetState(stDone)
+}
+
+// SetPartial is a no-op: WaylandPopup has no font to render the partial
+// transcript with (kept for interface parity with X11Popup.SetPartial).
+func (p *WaylandPopup) SetPartial(string) {}
+
+func (p *WaylandPopup) Hide() {
+	p.mu.Lock()
+	p.state = stHidden
+	p.mu.Unlock()
+	p.surface.Attach(nil, 0, 0) // detach: unmaps the layer surface per wl_surface semantics
+	p.surface.Commit()
+}
+
+func (p *WaylandPopup) Close() {
+	select {
+	case <-p.stopCh:
+	default:
+		close(p.stopCh)
+	}
+	if p.buffer != nil {
+		p.buffer.Destroy()
+	}
+	if p.pool != nil {
+		p.pool.Destroy()
+	}
+	if p.mem != nil {
+		unix.Munmap(p.mem)
+	}
+	if p.layerSurf != nil {
+		p.layerSurf.Destroy()
+	}
+	if p.surface != nil {
+		p.surface.Destroy()
+	}
+	p.display.Context().Close()
+}
+
+func (p *WaylandPopup) eventLoop() {
+	for {
+		if err := p.display.Context().Dispatch(); err != nil {
+			return
+		}
+		select {
+		case <-p.stopCh:
+			return
+		default:
+		}
+	}
+}
+
+func (p *WaylandPopup) renderLoop() {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	frame := 0
+	for {
+		select {
+		case <-ticker.C:
+			p.mu.Lock()
+			s, ready := p.state, p.configured
+			p.mu.Unlock()
+			if s != stHidden && ready {
+				p.drawFrame(s, frame)
+				frame++
+			} else {
+				frame = 0
+			}
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+func (p *WaylandPopup) commit() {
+	p.mu.Lock()
+	s := p.state
+	p.mu.Unlock()
+	if s != stHidden {
+		p.drawFrame(s, 0)
+	}
+}
+
+// drawFrame rasterizes the current state into the SHM buffer and commits it.
+func (p *WaylandPopup) drawFrame(s popState, frame int) {
+	w, h := int32(popSz), int32(popSz)
+	if w != p.w || h != p.h {
+		if err := p.allocBuffer(w, h); err != nil {
+			return
+		}
+	}
+
+	rasterClear(p.mem, int(w), int(h), popBG)
+	switch s {
+	case stListening:
+		rasterPulsingDot(p.mem, int(w), int(h), frame)
+	case stProcessing:
+		rasterSpinningArc(p.mem, int(w), int(h), frame)
+	case stDone:
+		rasterDot(p.mem, int(w), int(h), 0x30D158)
+	case stError:
+		rasterDot(p.mem, int(w), int(h), 0xFF3B30)
+	}
+
+	p.surface.Attach(p.buffer, 0, 0)
+	p.surface.DamageBuffer(0, 0, w, h)
+	p.surface.Commit()
+}
+
+// queryCaretPos prefers AT-SPI2 (shared with X11Popup, since the
+// accessibility bus is desktop-wide rather than display-server-specific),
+// then falls back to the output's logical center. Standard Wayland
+// protocols deliberately don't expose other clients' window geometry the
+// way xdotool does under X11, so unlike X11Popup there is no
+// focused-window-geometry fallback between those two.
+func (p *WaylandPopup) queryCaretPos() (int32, int32) {
+	if x, y, ok := queryCaretViaAtspi(); ok {
+		return int32(x), int32(y)
+	}
+	p.logMu.Lock()
+	x, y, w, h, ok := p.logX, p.logY, p.logW, p.logH, p.haveLogical
+	p.logMu.Unlock()
+	if ok {
+		return x + w/2, y + h/2
+	}
+	return popSz, popSz
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// rasterSetPixel writes one opaque ARGB8888 pixel into an SHM-backed buffer
+// of width w, silently clipping anything outside its bounds.
+func rasterSetPixel(mem []byte, w, x, y int, colorRGB uint32) {
+	if x < 0 || y < 0 || x >= w {
+		return
+	}
+	off := (y*w + x) * 4
+	if off < 0 || off+4 > len(mem) {
+		return
+	}
+	binary.LittleEndian.PutUint32(mem[off:], 0xFF000000|colorRGB)
+}
+
+// rasterClear fills the whole buffer with a solid color, standing in for
+// X11Popup's PolyFillRectangle background clear.
+func rasterClear(mem []byte, w, h int, colorRGB uint32) {
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			rasterSetPixel(mem, w, x, y, colorRGB)
+		}
+	}
+}
+
+// rasterFillCircle fills a disc of radius r centered at (cx, cy), standing
+// in for X11Popup.fillCircle's PolyFillArc.
+func rasterFillCircle(mem []byte, w, h, cx, cy, r int, colorRGB uint32) {
+	r2 := r * r
+	for y := cy - r; y <= cy+r; y++ {
+		if y < 0 || y >= h {
+			continue
+		}
+		for x := cx - r; x <= cx+r; x++ {
+			dx, dy := x-cx, y-cy
+			if dx*dx+dy*dy <= r2 {
+				rasterSetPixel(mem, w, x, y, colorRGB)
+			}
+		}
+	}
+}
+
+// rasterDot draws the plain done/error indicator — WaylandPopup has no font
+// to render X11Popup's text preview alongside it, so it always shows this.
+func rasterDot(mem []byte, w, h int, colorRGB uint32) {
+	rasterFillCircle(mem, w, h, w/2, h/2, 17, colorRGB)
+}
+
+// rasterPulsingDot mirrors X11Popup.drawListening: a red dot whose radius
+// breathes in and out over a 40-frame cycle.
+func rasterPulsingDot(mem []byte, w, h, frame int) {
+	t := float64(frame) * 2 * math.Pi / 40
+	r := int(12 + 5*math.Sin(t))
+	rasterFillCircle(mem, w, h, w/2, h/2, r, 0xFF3B30)
+}
+
+// rasterSpinningArc mirrors X11Popup.drawProcessing: a dim full ring with a
+// brighter segment that sweeps around it.
+func rasterSpinningArc(mem []byte, w, h, frame int) {
+	cx, cy := w/2, h/2
+	const (
+		arcR  = 17
+		lineW = 4
+		sweep = 100.0
+	)
+	start := float64((frame * 18) % 360)
+
+	for y := cy - arcR - lineW; y <= cy+arcR+lineW; y++ {
+		if y < 0 || y >= h {
+			continue
+		}
+		for x := cx - arcR - lineW; x <= cx+arcR+lineW; x++ {
+			dx, dy := float64(x-cx), float64(y-cy)
+			dist := math.Hypot(dx, dy)
+			if dist < arcR-lineW/2.0 || dist > arcR+lineW/2.0 {
+				continue
+			}
+			angle := math.Mod(math.Atan2(dy, dx)*180/math.Pi+360, 360)
+			if angleInArc(angle, start, sweep) {
+				rasterSetPixel(mem, w, x, y, 0x0A84FF)
+			} else {
+				rasterSetPixel(mem, w, x, y, 0x0A3060)
+			}
+		}
+	}
+}
+
+// angleInArc reports whether angle (degrees, in [0,360)) falls within
+// sweep degrees of start, going clockwise.
+func angleInArc(angle, start, sweep float64) bool {
+	d := math.Mod(angle-start+360, 360)
+	return d <= sweep
+}
@@ -0,0 +1,140 @@
+// Package vosk provides an offline speech-recognition backend built on the
+// Vosk Go bindings (CGo, libvosk), plus a small manager that fetches and
+// unpacks the model directories those bindings need.
+package vosk
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultModelBaseURL is the upstream model bucket vosk's own documentation
+// points users at. Override via EnsureModel's baseURL parameter.
+const defaultModelBaseURL = "https://alphacephei.com/vosk/models"
+
+// CacheDir returns the directory models are unpacked into:
+// ~/.cache/gotalk-dictation/vosk-models/.
+func CacheDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cache", "gotalk-dictation", "vosk-models")
+}
+
+// EnsureModel returns the local path to the unpacked model directory for
+// name (e.g. "vosk-model-small-en-us-0.15"), downloading and unzipping it
+// from baseURL into CacheDir() if it isn't already present. Pass "" for
+// baseURL to use the upstream model bucket.
+func EnsureModel(name, baseURL string) (string, error) {
+	if baseURL == "" {
+		baseURL = defaultModelBaseURL
+	}
+
+	dir := CacheDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating model cache dir: %w", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return path, nil
+	}
+
+	url := baseURL + "/" + name + ".zip"
+	if err := downloadAndUnzip(url, name, dir); err != nil {
+		return "", fmt.Errorf("downloading model %q: %w", name, err)
+	}
+	return path, nil
+}
+
+// downloadAndUnzip streams url to a temp file, extracts it under a temp
+// sibling directory, then renames the model's top-level folder into place
+// so a failed or partial download never leaves a half-unpacked model at
+// dir/name.
+func downloadAndUnzip(url, name, dir string) error {
+	resp, err := http.Get(url) //nolint:gosec // url is built from a configurable model base, not user input
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	zipPath := filepath.Join(dir, name+".zip.part")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		return err
+	}
+	_, copyErr := io.Copy(f, resp.Body)
+	closeErr := f.Close()
+	defer os.Remove(zipPath) //nolint:errcheck
+	if copyErr != nil {
+		return copyErr
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	extractDir := filepath.Join(dir, name+".part")
+	os.RemoveAll(extractDir) //nolint:errcheck
+	if err := unzip(zipPath, extractDir); err != nil {
+		os.RemoveAll(extractDir) //nolint:errcheck
+		return err
+	}
+	defer os.RemoveAll(extractDir) //nolint:errcheck
+
+	// The archive contains a single top-level "name/" directory; move that
+	// into place rather than extractDir itself, since zip.OpenReader gives
+	// us entries prefixed with it.
+	return os.Rename(filepath.Join(extractDir, name), filepath.Join(dir, name))
+}
+
+func unzip(src, destDir string) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		path := filepath.Join(destDir, f.Name)
+		if !strings.HasPrefix(path, filepath.Clean(destDir)+string(os.PathSeparator)) {
+			return fmt.Errorf("illegal file path in archive: %s", f.Name)
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := extractFile(f, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractFile(f *zip.File, path string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, rc)
+	return err
+}
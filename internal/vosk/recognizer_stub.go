@@ -0,0 +1,26 @@
+//go:build !vosk
+
+package vosk
+
+import (
+	"context"
+	"fmt"
+)
+
+// Recognizer is a stand-in used when the binary is built without Vosk
+// support. Build with `-tags vosk` (and a working libvosk) to get the real
+// offline backend.
+type Recognizer struct{}
+
+// New always fails: this build was compiled without Vosk support.
+func New(modelPath string) (*Recognizer, error) {
+	return nil, fmt.Errorf("vosk-local backend requires building with -tags vosk")
+}
+
+// Recognize is unreachable; New always returns an error.
+func (r *Recognizer) Recognize(ctx context.Context, pcmS16LE []byte, sampleRate int, lang string) (string, error) {
+	return "", fmt.Errorf("vosk-local backend requires building with -tags vosk")
+}
+
+// Close is a no-op for the stub.
+func (r *Recognizer) Close() error { return nil }
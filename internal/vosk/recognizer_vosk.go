@@ -0,0 +1,63 @@
+//go:build vosk
+
+package vosk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	vsk "github.com/alphacep/vosk-api/go"
+)
+
+// Recognizer is an offline, on-device speech-to-text backend backed by
+// Vosk. It satisfies speech.Backend.
+type Recognizer struct {
+	mu    sync.Mutex
+	model *vsk.VoskModel
+}
+
+// New loads the model directory at modelPath. Loading is the expensive part
+// of Vosk startup, so Recognizer is meant to be built once and reused.
+func New(modelPath string) (*Recognizer, error) {
+	model, err := vsk.NewModel(modelPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading vosk model %q: %w", modelPath, err)
+	}
+	return &Recognizer{model: model}, nil
+}
+
+// Recognize transcribes 16-bit signed little-endian mono PCM at sampleRate.
+// Vosk recognizers are not safe for concurrent use, so calls are serialized
+// on a per-Recognizer mutex; lang is ignored since Vosk models are
+// single-language (select via model path/config instead).
+func (r *Recognizer) Recognize(ctx context.Context, pcmS16LE []byte, sampleRate int, lang string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec, err := vsk.NewRecognizer(r.model, float64(sampleRate))
+	if err != nil {
+		return "", fmt.Errorf("creating vosk recognizer: %w", err)
+	}
+	defer rec.Free()
+
+	rec.AcceptWaveform(pcmS16LE)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(rec.FinalResult()), &result); err != nil {
+		return "", fmt.Errorf("parsing vosk result: %w", err)
+	}
+	return result.Text, nil
+}
+
+// Close releases the underlying Vosk model.
+func (r *Recognizer) Close() error {
+	r.model.Free()
+	return nil
+}
@@ -0,0 +1,95 @@
+// Package whisper provides an offline speech-recognition backend built on
+// whisper.cpp's Go bindings (CGo), plus a small manager that fetches the
+// ggml model files those bindings need.
+package whisper
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// defaultModelBaseURL is the upstream ggml model bucket used by whisper.cpp's
+// own download script. Override via EnsureModel's baseURL parameter.
+const defaultModelBaseURL = "https://huggingface.co/ggerganov/whisper.cpp/resolve/main"
+
+// minModelBytes is a sanity floor: even the smallest ggml model is tens of
+// megabytes, so anything under this indicates a truncated or failed download.
+const minModelBytes = 10 << 20
+
+// CacheDir returns the directory models are downloaded into:
+// ~/.cache/gotalk-dictation/models/.
+func CacheDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cache", "gotalk-dictation", "models")
+}
+
+// ModelFileName returns the ggml file name for a model size, e.g. "base.en" -> "ggml-base.en.bin".
+func ModelFileName(size string) string {
+	return fmt.Sprintf("ggml-%s.bin", size)
+}
+
+// EnsureModel returns the local path to the ggml model for size, downloading
+// it from baseURL into CacheDir() if it isn't already present. Pass "" for
+// baseURL to use the upstream whisper.cpp model bucket.
+func EnsureModel(size, baseURL string) (string, error) {
+	if baseURL == "" {
+		baseURL = defaultModelBaseURL
+	}
+
+	dir := CacheDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating model cache dir: %w", err)
+	}
+
+	path := filepath.Join(dir, ModelFileName(size))
+	if info, err := os.Stat(path); err == nil && info.Size() >= minModelBytes {
+		return path, nil
+	}
+
+	url := baseURL + "/" + ModelFileName(size)
+	if err := downloadModel(url, path); err != nil {
+		return "", fmt.Errorf("downloading model %q: %w", size, err)
+	}
+	return path, nil
+}
+
+// downloadModel streams url into a temp file beside dst, verifies its size,
+// then renames it into place so a failed download never leaves a partial
+// model file at dst.
+func downloadModel(url, dst string) error {
+	resp, err := http.Get(url) //nolint:gosec // url is built from a configurable model base, not user input
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	tmp := dst + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	n, err := io.Copy(f, resp.Body)
+	closeErr := f.Close()
+	if err != nil {
+		os.Remove(tmp) //nolint:errcheck
+		return err
+	}
+	if closeErr != nil {
+		os.Remove(tmp) //nolint:errcheck
+		return closeErr
+	}
+	if n < minModelBytes {
+		os.Remove(tmp) //nolint:errcheck
+		return fmt.Errorf("downloaded file is only %d bytes, expected at least %d", n, minModelBytes)
+	}
+
+	return os.Rename(tmp, dst)
+}
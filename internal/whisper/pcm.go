@@ -0,0 +1,50 @@
+package whisper
+
+import "encoding/binary"
+
+// whisperSampleRate is the sample rate whisper.cpp expects its input at.
+const whisperSampleRate = 16000
+
+// pcmToFloat32 converts 16-bit signed little-endian PCM samples to float32
+// samples in the range [-1, 1], as required by whisper.cpp's Process().
+func pcmToFloat32(pcm []byte) []float32 {
+	n := len(pcm) / 2
+	out := make([]float32, n)
+	for i := 0; i < n; i++ {
+		s := int16(binary.LittleEndian.Uint16(pcm[i*2 : i*2+2]))
+		out[i] = float32(s) / 32768
+	}
+	return out
+}
+
+// resampleTo16kHz linearly resamples samples from sampleRate to the 16 kHz
+// whisper.cpp requires. It is a no-op when sampleRate is already 16000.
+func resampleTo16kHz(samples []float32, sampleRate int) []float32 {
+	if sampleRate == whisperSampleRate || len(samples) == 0 {
+		return samples
+	}
+
+	ratio := float64(whisperSampleRate) / float64(sampleRate)
+	outLen := int(float64(len(samples)) * ratio)
+	out := make([]float32, outLen)
+	for i := range out {
+		srcPos := float64(i) / ratio
+		i0 := int(srcPos)
+		i1 := i0 + 1
+		if i1 >= len(samples) {
+			i1 = len(samples) - 1
+		}
+		frac := float32(srcPos - float64(i0))
+		out[i] = samples[i0] + (samples[i1]-samples[i0])*frac
+	}
+	return out
+}
+
+// whisperLanguageCode maps a BCP-47 language code (e.g. "en-US") to the
+// two-letter code whisper.cpp's SetLanguage expects (e.g. "en").
+func whisperLanguageCode(bcp47 string) string {
+	if len(bcp47) >= 2 {
+		return bcp47[:2]
+	}
+	return bcp47
+}
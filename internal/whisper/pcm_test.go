@@ -0,0 +1,55 @@
+package whisper
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func makePCM(samples ...int16) []byte {
+	buf := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(s))
+	}
+	return buf
+}
+
+func TestPCMToFloat32(t *testing.T) {
+	got := pcmToFloat32(makePCM(0, 32767, -32768))
+	want := []float32{0, 32767.0 / 32768, -1}
+	for i := range want {
+		if math.Abs(float64(got[i]-want[i])) > 1e-6 {
+			t.Errorf("sample %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestResampleTo16kHzNoOp(t *testing.T) {
+	in := []float32{0.1, 0.2, 0.3}
+	out := resampleTo16kHz(in, whisperSampleRate)
+	if len(out) != len(in) {
+		t.Fatalf("len = %d, want %d", len(out), len(in))
+	}
+}
+
+func TestResampleTo16kHzDownsamples(t *testing.T) {
+	// 48kHz -> 16kHz should produce roughly a third as many samples.
+	in := make([]float32, 480)
+	out := resampleTo16kHz(in, 48000)
+	if len(out) != 160 {
+		t.Errorf("len = %d, want 160", len(out))
+	}
+}
+
+func TestWhisperLanguageCode(t *testing.T) {
+	tests := map[string]string{
+		"en-US": "en",
+		"fa-IR": "fa",
+		"en":    "en",
+	}
+	for in, want := range tests {
+		if got := whisperLanguageCode(in); got != want {
+			t.Errorf("whisperLanguageCode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
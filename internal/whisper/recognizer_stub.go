@@ -0,0 +1,26 @@
+//go:build !whisper
+
+package whisper
+
+import (
+	"context"
+	"fmt"
+)
+
+// Recognizer is a stand-in used when the binary is built without whisper.cpp
+// support. Build with `-tags whisper` (and a working libwhisper) to get the
+// real offline backend.
+type Recognizer struct{}
+
+// New always fails: this build was compiled without whisper.cpp support.
+func New(modelPath string, threads int) (*Recognizer, error) {
+	return nil, fmt.Errorf("whisper-local backend requires building with -tags whisper")
+}
+
+// Recognize is unreachable; New always returns an error.
+func (r *Recognizer) Recognize(ctx context.Context, pcmS16LE []byte, sampleRate int, lang string) (string, error) {
+	return "", fmt.Errorf("whisper-local backend requires building with -tags whisper")
+}
+
+// Close is a no-op for the stub.
+func (r *Recognizer) Close() error { return nil }
@@ -0,0 +1,75 @@
+//go:build whisper
+
+package whisper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	wsp "github.com/ggerganov/whisper.cpp/bindings/go"
+)
+
+// Recognizer is an offline, on-device speech-to-text backend backed by
+// whisper.cpp. It satisfies speech.Backend.
+type Recognizer struct {
+	Threads int
+
+	mu    sync.Mutex
+	model wsp.Model
+}
+
+// New loads the ggml model at modelPath. Loading is the expensive part of
+// whisper.cpp startup, so Recognizer is meant to be built once and reused.
+func New(modelPath string, threads int) (*Recognizer, error) {
+	model, err := wsp.New(modelPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading whisper model %q: %w", modelPath, err)
+	}
+	return &Recognizer{Threads: threads, model: model}, nil
+}
+
+// Recognize transcribes 16-bit signed little-endian mono PCM at sampleRate.
+// whisper.cpp contexts are not safe for concurrent use, so calls are
+// serialized on a per-Recognizer mutex.
+func (r *Recognizer) Recognize(ctx context.Context, pcmS16LE []byte, sampleRate int, lang string) (string, error) {
+	samples := resampleTo16kHz(pcmToFloat32(pcmS16LE), sampleRate)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	wctx, err := r.model.NewContext()
+	if err != nil {
+		return "", fmt.Errorf("creating whisper context: %w", err)
+	}
+	if r.Threads > 0 {
+		wctx.SetThreads(uint(r.Threads))
+	}
+	if lang != "" {
+		if err := wctx.SetLanguage(whisperLanguageCode(lang)); err != nil {
+			return "", fmt.Errorf("setting whisper language: %w", err)
+		}
+	}
+
+	if err := wctx.Process(samples, nil, nil); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("whisper process: %w", err)
+	}
+
+	var text string
+	for {
+		segment, err := wctx.NextSegment()
+		if err != nil {
+			break
+		}
+		text += segment.Text
+	}
+	return text, nil
+}
+
+// Close releases the underlying whisper.cpp model.
+func (r *Recognizer) Close() error {
+	return r.model.Close()
+}
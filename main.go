@@ -2,137 +2,347 @@ package main
 
 import (
 	"context"
+	"flag"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/Alijeyrad/gotalk-dictation/internal/audio"
 	"github.com/Alijeyrad/gotalk-dictation/internal/config"
+	"github.com/Alijeyrad/gotalk-dictation/internal/events"
 	"github.com/Alijeyrad/gotalk-dictation/internal/hotkey"
+	"github.com/Alijeyrad/gotalk-dictation/internal/ipc"
+	"github.com/Alijeyrad/gotalk-dictation/internal/notify"
 	"github.com/Alijeyrad/gotalk-dictation/internal/speech"
 	"github.com/Alijeyrad/gotalk-dictation/internal/typing"
 	"github.com/Alijeyrad/gotalk-dictation/internal/ui"
+	"github.com/Alijeyrad/gotalk-dictation/internal/ui/headless"
+	"github.com/Alijeyrad/gotalk-dictation/internal/ui/repl"
+	"github.com/Alijeyrad/gotalk-dictation/internal/ui/tui"
 )
 
+// uiFrontend is implemented by ui.Tray (Fyne) and tui.Console (tcell), so
+// main can drive either one through the same calls. Headless mode (no
+// display, no terminal) and REPL mode (an interactive terminal prompt)
+// bypass this entirely and run headless.Serve / repl.Console.Run instead.
+type uiFrontend interface {
+	Run(cfg *config.Config, onDictate func(), onQuit func(), startupErr error)
+	UpdateConfig(*config.Config)
+	SetOnSettingsSave(func(*config.Config))
+	SetListening()
+	SetProcessing()
+	SetPartial(string)
+	SetDone(string)
+	SetIdle()
+	SetError(string)
+}
+
+// noopFrontend discards every call; it backs app.tray in --headless mode, so
+// the dictation path's a.tray.SetXxx calls don't need headless-mode guards.
+type noopFrontend struct{}
+
+func (noopFrontend) Run(*config.Config, func(), func(), error) {}
+func (noopFrontend) UpdateConfig(*config.Config)               {}
+func (noopFrontend) SetOnSettingsSave(func(*config.Config))    {}
+func (noopFrontend) SetListening()                             {}
+func (noopFrontend) SetProcessing()                            {}
+func (noopFrontend) SetPartial(string)                         {}
+func (noopFrontend) SetDone(string)                            {}
+func (noopFrontend) SetIdle()                                  {}
+func (noopFrontend) SetError(string)                           {}
+
 type app struct {
 	cfgMu      sync.RWMutex
 	cfg        *config.Config
 	recorder   *audio.Recorder
 	recognizer *speech.Recognizer
-	typer      *typing.Typer
-	tray       *ui.Tray
+	typer      typing.Typer
+	tray       uiFrontend
+	events     *events.Emitter
+	notifier   *notify.Notifier
+	ipc        *ipc.Server
 
-	hkmMu   sync.Mutex
-	hkm     *hotkey.Manager // toggle hotkey
-	pttHkm  *hotkey.Manager // push-to-talk hotkey (independent)
-	undoHkm *hotkey.Manager
+	hkMu sync.Mutex
+	hk   hotkey.Bindings // shared hotkey backend connection for every hotkey
 
 	mu          sync.Mutex
 	isListening bool
 	cancelDicta context.CancelFunc
+
+	macroMu  sync.Mutex
+	macroRec *typing.MacroRecorder
+
+	// transcriptSink, if set, receives every non-streaming, non-macro
+	// transcript instead of having it typed automatically — the REPL
+	// frontend (--repl) uses this to show the transcript at its prompt for
+	// editing before the user commits it via Enter, which calls a.typer.Type
+	// itself. Every other frontend leaves it nil and gets the usual
+	// auto-type-on-recognition behavior.
+	transcriptSink func(text string)
 }
 
+var (
+	jsonEvents     = flag.Bool("json", false, "emit newline-delimited JSON lifecycle events on stdout")
+	tuiFlag        = flag.Bool("tui", false, "run the terminal (tcell) frontend instead of the system tray")
+	headlessSocket = flag.String("headless", "", "run with no UI at all, serving JSON-RPC control on this Unix socket path")
+	ipcFlag        = flag.Bool("ipc", false, "expose an event-stream and command Unix socket at ipc.SocketPath() for external integrations")
+	replFlag       = flag.Bool("repl", false, "run an interactive peterh/liner REPL instead of the tray/TUI, for terminal-only sessions")
+)
+
 func main() {
+	flag.Parse()
+
 	cfg, err := config.Load()
 	if err != nil {
 		cfg = config.Default()
 	}
 
+	var emitter *events.Emitter
+	var broadcaster *events.Broadcaster
+	if *jsonEvents {
+		emitter = events.NewEmitter(os.Stdout)
+	}
+	if cfg.EventsSocket != "" {
+		if b, berr := events.Listen(cfg.EventsSocket); berr == nil {
+			broadcaster = b
+			if emitter == nil {
+				emitter = events.NewEmitter(b)
+			}
+		}
+	}
+
+	var frontend uiFrontend
+	switch {
+	case *headlessSocket != "", *replFlag:
+		frontend = noopFrontend{}
+	case *tuiFlag:
+		frontend = &tui.Console{}
+	default:
+		frontend = &ui.Tray{}
+	}
+
 	a := &app{
 		cfg:      cfg,
 		recorder: &audio.Recorder{},
-		typer:    &typing.Typer{EnablePunctuation: cfg.EnablePunctuation},
-		tray:     &ui.Tray{},
+		typer:    typing.NewTyper(typing.Backend(cfg.TypingBackend), cfg.EnablePunctuation),
+		tray:     frontend,
+		events:   emitter,
+		notifier: buildNotifier(cfg),
 	}
 	a.recognizer = buildRecognizer(cfg)
+	a.recognizer.Events = a.events
 
 	var startupErr error
 
-	// Register toggle hotkey.
-	hkm, err := hotkey.New(cfg.Hotkey)
+	// Every hotkey shares one Bindings instance (one X11 connection/event
+	// loop) instead of opening a connection per chord.
+	hk, err := hotkey.NewBindings()
 	if err != nil {
 		startupErr = err
 	} else {
-		if err := hkm.Register(a.toggleDictation); err != nil {
-			hkm.Stop() // close the X11 connection; grab failed
+		a.hkMu.Lock()
+		a.hk = hk
+		a.hkMu.Unlock()
+
+		if err := hk.Bind(cfg.Hotkey, hotkey.Action{OnPress: a.toggleDictation}); err != nil {
 			startupErr = err
-		} else {
-			a.hkmMu.Lock()
-			a.hkm = hkm
-			a.hkmMu.Unlock()
+		}
+		// PTT, undo, and cycle-profile errors are non-fatal — the app still
+		// works with just the toggle hotkey.
+		if cfg.PTTHotkey != "" {
+			hk.Bind(cfg.PTTHotkey, hotkey.Action{OnPress: a.startDictation, OnRelease: a.recorder.Stop}) //nolint:errcheck
+		}
+		if cfg.UndoHotkey != "" {
+			hk.Bind(cfg.UndoHotkey, hotkey.Action{OnPress: a.undoLastDictation}) //nolint:errcheck
+		}
+		if cfg.CycleProfileHotkey != "" {
+			hk.Bind(cfg.CycleProfileHotkey, hotkey.Action{OnPress: a.cycleProfile}) //nolint:errcheck
+		}
+		if cfg.MacroRecordHotkey != "" {
+			hk.Bind(cfg.MacroRecordHotkey, hotkey.Action{OnPress: a.toggleMacroRecording}) //nolint:errcheck
 		}
 	}
 
-	// Register push-to-talk hotkey (independent from toggle).
-	if cfg.PTTHotkey != "" {
-		if pttHkm, err := hotkey.New(cfg.PTTHotkey); err == nil {
-			if err := pttHkm.RegisterPushToTalk(a.startDictation, a.recorder.Stop); err == nil {
-				a.hkmMu.Lock()
-				a.pttHkm = pttHkm
-				a.hkmMu.Unlock()
-			} else {
-				pttHkm.Stop() // close the X11 connection; grab failed
+	// Watch config.json for external edits (e.g. a text editor) and apply
+	// hotkey/sensitivity/punctuation changes live, without restarting.
+	watcher, werr := config.NewWatcher(cfg)
+	if werr == nil {
+		go func() {
+			for newCfg := range watcher.Changes() {
+				a.applyLiveConfig(newCfg)
 			}
-		}
+		}()
 	}
 
-	if cfg.UndoHotkey != "" {
-		if uhkm, err := hotkey.New(cfg.UndoHotkey); err == nil {
-			if err := uhkm.Register(a.undoLastDictation); err != nil {
-				uhkm.Stop() // close the X11 connection; grab failed
-			} else {
-				a.hkmMu.Lock()
-				a.undoHkm = uhkm
-				a.hkmMu.Unlock()
-			}
+	// The IPC socket is opt-in: it coexists with whichever frontend is
+	// running rather than replacing it, so editor plugins and bar scripts
+	// can watch/drive dictation alongside the tray or TUI.
+	if *ipcFlag {
+		if s, ierr := ipc.Serve(ipc.SocketPath(), ipc.Handlers{
+			Toggle:       a.toggleDictation,
+			Start:        func() { go a.startDictation() },
+			Stop:         a.stopDictation,
+			Undo:         a.undoLastDictation,
+			SetLanguage:  a.setLanguage,
+			Type:         a.ipcType,
+			GetClipboard: a.ipcGetClipboard,
+			SetClipboard: a.ipcSetClipboard,
+		}); ierr == nil {
+			a.ipc = s
 		}
 	}
 
-	a.tray.OnSettingsSave = func(newCfg *config.Config) {
+	a.tray.SetOnSettingsSave(func(newCfg *config.Config) {
 		newCfg.Save() //nolint:errcheck
 
 		a.cfgMu.RLock()
 		oldHotkey := a.cfg.Hotkey
 		oldPTTHotkey := a.cfg.PTTHotkey
 		oldUndoHotkey := a.cfg.UndoHotkey
+		oldCycleProfileHotkey := a.cfg.CycleProfileHotkey
 		a.cfgMu.RUnlock()
 
 		a.cfgMu.Lock()
 		a.cfg = newCfg
 		a.recognizer = buildRecognizer(newCfg)
-		a.typer = &typing.Typer{EnablePunctuation: newCfg.EnablePunctuation}
+		a.recognizer.Events = a.events
+		a.typer = typing.NewTyper(typing.Backend(newCfg.TypingBackend), newCfg.EnablePunctuation)
+		a.notifier = buildNotifier(newCfg)
 		a.cfgMu.Unlock()
 		a.tray.UpdateConfig(newCfg)
 
 		if newCfg.Hotkey != oldHotkey {
-			a.rebindHotkey(newCfg.Hotkey)
+			a.rebindHotkey(oldHotkey, newCfg.Hotkey)
 		}
 		if newCfg.PTTHotkey != oldPTTHotkey {
-			a.rebindPTTHotkey(newCfg.PTTHotkey)
+			a.rebindPTTHotkey(oldPTTHotkey, newCfg.PTTHotkey)
 		}
 		if newCfg.UndoHotkey != oldUndoHotkey {
-			a.rebindUndoHotkey(newCfg.UndoHotkey)
+			a.rebindUndoHotkey(oldUndoHotkey, newCfg.UndoHotkey)
 		}
-	}
+		if newCfg.CycleProfileHotkey != oldCycleProfileHotkey {
+			a.rebindCycleProfileHotkey(oldCycleProfileHotkey, newCfg.CycleProfileHotkey)
+		}
+	})
 
-	a.tray.Run(cfg, a.toggleDictation, func() {
+	onQuit := func() {
 		a.mu.Lock()
 		cancel := a.cancelDicta
 		a.mu.Unlock()
 		if cancel != nil {
 			cancel()
 		}
-		a.hkmMu.Lock()
-		if a.hkm != nil {
-			a.hkm.Stop()
+		a.hkMu.Lock()
+		if a.hk != nil {
+			a.hk.Stop()
 		}
-		if a.pttHkm != nil {
-			a.pttHkm.Stop()
+		a.hkMu.Unlock()
+		if watcher != nil {
+			watcher.Close() //nolint:errcheck
 		}
-		if a.undoHkm != nil {
-			a.undoHkm.Stop()
+		a.events.Close() //nolint:errcheck
+		if broadcaster != nil {
+			broadcaster.Close() //nolint:errcheck
 		}
-		a.hkmMu.Unlock()
-	}, startupErr)
+		a.ipc.Close() //nolint:errcheck
+	}
+
+	if *headlessSocket != "" {
+		// No Run loop to block on (noopFrontend.Run returns immediately), so
+		// headless.Serve blocks main itself until the socket is closed.
+		if err := headless.Serve(*headlessSocket, headless.Handlers{
+			Start: func() { go a.startDictation() },
+			Stop:  a.stopDictation,
+			Status: func() string {
+				a.mu.Lock()
+				defer a.mu.Unlock()
+				if a.isListening {
+					return "listening"
+				}
+				return "idle"
+			},
+			GetConfig: func() *config.Config {
+				a.cfgMu.RLock()
+				defer a.cfgMu.RUnlock()
+				return a.cfg
+			},
+			SetConfig: func(newCfg *config.Config) error {
+				newCfg.Save() //nolint:errcheck
+				a.applyLiveConfig(newCfg)
+				return nil
+			},
+		}); err != nil {
+			os.Exit(1)
+		}
+		onQuit()
+		return
+	}
+
+	if *replFlag {
+		// Like headless.Serve above, this blocks main itself — the REPL's
+		// own prompt loop is the UI, so there's no tray Run loop to block on
+		// (noopFrontend.Run returns immediately).
+		rc := repl.New(repl.Handlers{
+			Type: func(text string) error {
+				a.cfgMu.RLock()
+				typer := a.typer
+				a.cfgMu.RUnlock()
+				return typer.Type(text)
+			},
+			Undo:           a.undoLastDictation,
+			SetLanguage:    a.setLanguage,
+			SetPunctuation: a.setPunctuation,
+			RunMacro: func(name string) error {
+				a.cfgMu.RLock()
+				typer := a.typer
+				a.cfgMu.RUnlock()
+				return typer.PlayMacro(name)
+			},
+		})
+		a.transcriptSink = rc.OfferTranscript
+		rc.Run()
+		onQuit()
+		return
+	}
+
+	a.tray.Run(cfg, a.toggleDictation, onQuit, startupErr)
+}
+
+// applyLiveConfig reacts to an externally-edited config.json: it rebinds
+// whichever hotkeys changed and pushes sensitivity/punctuation updates into
+// the existing recognizer/typer in place, so an in-flight dictation keeps
+// running on its old settings rather than being dropped.
+func (a *app) applyLiveConfig(newCfg *config.Config) {
+	a.cfgMu.RLock()
+	oldHotkey := a.cfg.Hotkey
+	oldPTTHotkey := a.cfg.PTTHotkey
+	oldUndoHotkey := a.cfg.UndoHotkey
+	oldCycleProfileHotkey := a.cfg.CycleProfileHotkey
+	rec := a.recognizer
+	typer := a.typer
+	a.cfgMu.RUnlock()
+
+	rec.SetLiveConfig(newCfg.SilenceChunks, newCfg.Sensitivity)
+	typer.SetEnablePunctuation(newCfg.EnablePunctuation)
+
+	a.cfgMu.Lock()
+	a.cfg = newCfg
+	a.notifier = buildNotifier(newCfg)
+	a.cfgMu.Unlock()
+	a.tray.UpdateConfig(newCfg)
+
+	if newCfg.Hotkey != oldHotkey {
+		a.rebindHotkey(oldHotkey, newCfg.Hotkey)
+	}
+	if newCfg.PTTHotkey != oldPTTHotkey {
+		a.rebindPTTHotkey(oldPTTHotkey, newCfg.PTTHotkey)
+	}
+	if newCfg.UndoHotkey != oldUndoHotkey {
+		a.rebindUndoHotkey(oldUndoHotkey, newCfg.UndoHotkey)
+	}
+	if newCfg.CycleProfileHotkey != oldCycleProfileHotkey {
+		a.rebindCycleProfileHotkey(oldCycleProfileHotkey, newCfg.CycleProfileHotkey)
+	}
 }
 
 func (a *app) undoLastDictation() {
@@ -140,84 +350,214 @@ func (a *app) undoLastDictation() {
 	typer := a.typer
 	a.cfgMu.RUnlock()
 	typer.Undo() //nolint:errcheck
+	a.events.Undo()
+	a.notifier.Undo()
 }
 
-func (a *app) rebindHotkey(newHotkey string) {
-	a.hkmMu.Lock()
-	defer a.hkmMu.Unlock()
+// ipcType types text through the current typer, for the IPC "type" command
+// (see internal/ipc) — lets a companion script or phone app inject text
+// without going through the speech pipeline at all.
+func (a *app) ipcType(text string) error {
+	a.cfgMu.RLock()
+	typer := a.typer
+	a.cfgMu.RUnlock()
+	return typer.Type(text)
+}
 
-	if a.hkm != nil {
-		a.hkm.Stop()
-		a.hkm = nil
+// ipcGetClipboard and ipcSetClipboard back the IPC "get_clipboard" and
+// "set_clipboard" commands, so a remote session can mirror the clipboard
+// the way a KVM-over-web tool does.
+func (a *app) ipcGetClipboard() string {
+	a.cfgMu.RLock()
+	typer := a.typer
+	a.cfgMu.RUnlock()
+	return typer.GetClipboard()
+}
+
+func (a *app) ipcSetClipboard(text string) error {
+	a.cfgMu.RLock()
+	typer := a.typer
+	a.cfgMu.RUnlock()
+	return typer.SetClipboardAndPaste(text)
+}
+
+// cycleProfile rotates the active profile and rebuilds the recognizer/typer
+// from it, the same way a settings save would. It's a no-op (no toast) if
+// fewer than two profiles are configured.
+func (a *app) cycleProfile() {
+	a.cfgMu.Lock()
+	name, err := a.cfg.CycleProfile()
+	cfg := a.cfg
+	a.cfgMu.Unlock()
+	if err != nil || name == "" {
+		return
 	}
+	cfg.Save() //nolint:errcheck
 
-	hkm, err := hotkey.New(newHotkey)
-	if err != nil {
-		a.tray.SetError("Hotkey invalid: " + newHotkey)
+	a.cfgMu.Lock()
+	a.recognizer = buildRecognizer(cfg)
+	a.recognizer.Events = a.events
+	a.typer = typing.NewTyper(typing.Backend(cfg.TypingBackend), cfg.EnablePunctuation)
+	a.cfgMu.Unlock()
+
+	a.tray.UpdateConfig(cfg)
+	a.tray.SetDone("Profile: " + name)
+}
+
+// toggleMacroRecording starts or stops XRecord-based macro capture on
+// a.cfg.MacroRecordHotkey: the first tap opens the recording, the second
+// saves it under the next free "macro-N" name and reports the name via the
+// tray so the user knows what to say (or rename the file) to replay it.
+func (a *app) toggleMacroRecording() {
+	a.macroMu.Lock()
+	defer a.macroMu.Unlock()
+
+	if a.macroRec == nil {
+		rec, err := typing.StartMacroRecording()
+		if err != nil {
+			a.tray.SetError("Macro record error: " + err.Error())
+			return
+		}
+		a.macroRec = rec
+		a.tray.SetDone("Recording macro...")
 		return
 	}
-	if err := hkm.Register(a.toggleDictation); err != nil {
-		hkm.Stop()
-		a.tray.SetError("Hotkey taken: " + newHotkey)
+
+	events := a.macroRec.Stop()
+	a.macroRec = nil
+
+	name := typing.NextMacroName()
+	if err := typing.SaveMacro(typing.Macro{Name: name, Events: events}); err != nil {
+		a.tray.SetError("Macro save error: " + err.Error())
 		return
 	}
-	a.hkm = hkm
+	a.tray.SetDone("Saved macro: " + name)
 }
 
-func (a *app) rebindPTTHotkey(newHotkey string) {
-	a.hkmMu.Lock()
-	defer a.hkmMu.Unlock()
+// setLanguage updates the recognition language and rebuilds the recognizer
+// from it, the same way a settings save would. It's driven by IPC clients
+// (e.g. a Rofi language switcher) that want to change language without
+// opening the settings window.
+func (a *app) setLanguage(lang string) {
+	a.cfgMu.Lock()
+	cfg := a.cfg
+	cfg.Language = lang
+	a.cfgMu.Unlock()
+	cfg.Save() //nolint:errcheck
+
+	a.cfgMu.Lock()
+	a.recognizer = buildRecognizer(cfg)
+	a.recognizer.Events = a.events
+	a.cfgMu.Unlock()
 
-	if a.pttHkm != nil {
-		a.pttHkm.Stop()
-		a.pttHkm = nil
-	}
+	a.tray.UpdateConfig(cfg)
+}
 
-	if newHotkey == "" {
-		return
+// setPunctuation toggles punctuation processing in place, the same way
+// applyLiveConfig does for an externally-edited config.json. It's driven by
+// the REPL's "/punct on|off" command, which wants to flip the setting
+// without opening the settings window.
+func (a *app) setPunctuation(on bool) {
+	a.cfgMu.Lock()
+	cfg := a.cfg
+	cfg.EnablePunctuation = on
+	typer := a.typer
+	a.cfgMu.Unlock()
+	cfg.Save() //nolint:errcheck
+
+	typer.SetEnablePunctuation(on)
+	a.tray.UpdateConfig(cfg)
+}
+
+// rebindHotkey swaps the toggle hotkey's chord on the shared Bindings
+// connection.
+func (a *app) rebindHotkey(oldHotkey, newHotkey string) {
+	a.hkMu.Lock()
+	defer a.hkMu.Unlock()
+
+	if oldHotkey != "" {
+		a.hk.Unbind(oldHotkey) //nolint:errcheck
+	}
+	if err := a.hk.Bind(newHotkey, hotkey.Action{OnPress: a.toggleDictation}); err != nil {
+		a.tray.SetError("Hotkey taken: " + newHotkey)
 	}
+}
 
-	pttHkm, err := hotkey.New(newHotkey)
-	if err != nil {
-		a.tray.SetError("PTT hotkey invalid: " + newHotkey)
+func (a *app) rebindPTTHotkey(oldHotkey, newHotkey string) {
+	a.hkMu.Lock()
+	defer a.hkMu.Unlock()
+
+	if oldHotkey != "" {
+		a.hk.Unbind(oldHotkey) //nolint:errcheck
+	}
+	if newHotkey == "" {
 		return
 	}
-	if err := pttHkm.RegisterPushToTalk(a.startDictation, a.recorder.Stop); err != nil {
-		pttHkm.Stop()
+	if err := a.hk.Bind(newHotkey, hotkey.Action{OnPress: a.startDictation, OnRelease: a.recorder.Stop}); err != nil {
 		a.tray.SetError("PTT hotkey taken: " + newHotkey)
-		return
 	}
-	a.pttHkm = pttHkm
 }
 
-func (a *app) rebindUndoHotkey(newHotkey string) {
-	a.hkmMu.Lock()
-	defer a.hkmMu.Unlock()
-	if a.undoHkm != nil {
-		a.undoHkm.Stop()
-		a.undoHkm = nil
+func (a *app) rebindUndoHotkey(oldHotkey, newHotkey string) {
+	a.hkMu.Lock()
+	defer a.hkMu.Unlock()
+
+	if oldHotkey != "" {
+		a.hk.Unbind(oldHotkey) //nolint:errcheck
 	}
 	if newHotkey == "" {
 		return
 	}
-	uhkm, err := hotkey.New(newHotkey)
-	if err != nil {
-		return
+	a.hk.Bind(newHotkey, hotkey.Action{OnPress: a.undoLastDictation}) //nolint:errcheck
+}
+
+func (a *app) rebindCycleProfileHotkey(oldHotkey, newHotkey string) {
+	a.hkMu.Lock()
+	defer a.hkMu.Unlock()
+
+	if oldHotkey != "" {
+		a.hk.Unbind(oldHotkey) //nolint:errcheck
 	}
-	if err := uhkm.Register(a.undoLastDictation); err != nil {
-		uhkm.Stop()
+	if newHotkey == "" {
 		return
 	}
-	a.undoHkm = uhkm
+	a.hk.Bind(newHotkey, hotkey.Action{OnPress: a.cycleProfile}) //nolint:errcheck
 }
 
 func buildRecognizer(cfg *config.Config) *speech.Recognizer {
 	return &speech.Recognizer{
-		Language:       cfg.Language,
-		APIKey:         cfg.APIKey,
-		UseAdvancedAPI: cfg.UseAdvancedAPI,
-		SilenceChunks:  cfg.SilenceChunks,
-		Sensitivity:    cfg.Sensitivity,
+		Language:         cfg.Language,
+		SilenceChunks:    cfg.SilenceChunks,
+		Sensitivity:      cfg.Sensitivity,
+		Backend:          cfg.Backend,
+		WhisperModelPath: cfg.WhisperModelPath,
+		WhisperModelSize: cfg.WhisperModelSize,
+		WhisperThreads:   cfg.WhisperThreads,
+		VoskModelPath:    cfg.VoskModelPath,
+		VoskModelName:    cfg.VoskModelName,
+		Vocabulary:       cfg.Vocabulary,
+
+		EnableAutomaticPunctuation: cfg.EnableAutomaticPunctuation,
+		AlternativeLanguageCodes:   cfg.AlternativeLanguageCodes,
+		Model:                      cfg.Model,
+		UseEnhanced:                cfg.UseEnhanced,
+		ProfanityFilter:            cfg.ProfanityFilter,
+		MaxAlternatives:            cfg.MaxAlternatives,
+		EnableWordTimeOffsets:      cfg.EnableWordTimeOffsets,
+		EnableWordConfidence:       cfg.EnableWordConfidence,
+		LongForm:                   cfg.LongForm,
+		GCSBucket:                  cfg.GCSBucket,
+	}
+}
+
+func buildNotifier(cfg *config.Config) *notify.Notifier {
+	return &notify.Notifier{
+		SoundEnabled:  cfg.SoundEnabled,
+		NotifyEnabled: cfg.NotifyEnabled,
+		SoundStart:    cfg.SoundStart,
+		SoundStop:     cfg.SoundStop,
+		SoundError:    cfg.SoundError,
+		SoundUndo:     cfg.SoundUndo,
 	}
 }
 
@@ -233,6 +573,12 @@ func (a *app) toggleDictation() {
 	}
 }
 
+// longFormMinTimeout is the session deadline floor startDictation enforces
+// when config.LongForm is on, in seconds — long enough to cover several
+// recognizeCloudLongForm stream rotations instead of the short default
+// Timeout meant for a single phrase.
+const longFormMinTimeout = 3600
+
 func (a *app) startDictation() {
 	a.mu.Lock()
 	if a.isListening {
@@ -242,7 +588,14 @@ func (a *app) startDictation() {
 	a.isListening = true
 	a.cfgMu.RLock()
 	timeout := a.cfg.Timeout
+	longForm := a.cfg.LongForm && a.cfg.Backend == config.BackendGoogleCloud
 	a.cfgMu.RUnlock()
+	if longForm && timeout < longFormMinTimeout {
+		// The default Timeout is sized for a single short phrase; long-form
+		// dictation needs the whole session to outlive it, not just the
+		// per-stream rotation in recognizeCloudLongForm.
+		timeout = longFormMinTimeout
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
 	a.cancelDicta = cancel
 	a.mu.Unlock()
@@ -256,46 +609,131 @@ func (a *app) startDictation() {
 	}()
 
 	a.tray.SetListening()
+	a.notifier.Start()
+	a.ipc.Emit(ipc.StateEvent("listening"))
 
+	start := time.Now()
 	audioCh, err := a.recorder.Start(ctx)
 	if err != nil {
 		a.tray.SetError("Mic error: " + err.Error())
+		a.notifier.Error(err.Error())
+		a.ipc.Emit(ipc.ErrorEvent(err.Error()))
 		return
 	}
 
 	a.cfgMu.RLock()
 	rec := a.recognizer
 	typer := a.typer
+	notifier := a.notifier
+	streaming := a.cfg.Streaming && a.cfg.Backend == config.BackendGoogleCloud
 	a.cfgMu.RUnlock()
 
-	rec.OnProcessing = func() { a.tray.SetProcessing() }
-
-	text, err := rec.Recognize(ctx, audioCh)
+	var text string
+	if streaming {
+		text, err = a.runStreamingDictation(ctx, rec, typer, audioCh)
+	} else {
+		text, err = rec.Recognize(ctx, audioCh)
+	}
 	a.recorder.Stop()
 
 	if err != nil {
 		switch ctx.Err() {
 		case context.DeadlineExceeded:
 			a.tray.SetError("Timeout")
+			notifier.Error("Timeout")
+			a.ipc.Emit(ipc.ErrorEvent("Timeout"))
 		case context.Canceled:
 			a.tray.SetIdle()
+			a.ipc.Emit(ipc.StateEvent("idle"))
 		default:
 			a.tray.SetError(err.Error())
+			notifier.Error(err.Error())
+			a.ipc.Emit(ipc.ErrorEvent(err.Error()))
 		}
 		return
 	}
 
 	if text == "" {
 		a.tray.SetError("Could not understand speech")
+		notifier.Error("Could not understand speech")
+		a.ipc.Emit(ipc.ErrorEvent("Could not understand speech"))
 		return
 	}
 
-	if err := typer.Type(text); err != nil {
-		a.tray.SetError("Type error: " + err.Error())
-		return
+	// A "run macro foo" / "macro:foo" phrase replays a recorded macro
+	// instead of being typed. Streaming mode has already typed each partial
+	// incrementally by the time the final transcript lands, so this only
+	// applies to the non-streaming path.
+	if !streaming {
+		if name, ok := typing.ParseMacroInvocation(text); ok {
+			if err := typer.PlayMacro(name); err != nil {
+				a.tray.SetError("Macro error: " + err.Error())
+				notifier.Error(err.Error())
+				a.ipc.Emit(ipc.ErrorEvent(err.Error()))
+				return
+			}
+			a.tray.SetDone("Ran macro: " + name)
+			notifier.Stop("Ran macro: " + name)
+			a.ipc.Emit(ipc.FinalEvent(text, time.Since(start).Milliseconds()))
+			return
+		}
+		if a.transcriptSink != nil {
+			a.transcriptSink(text)
+		} else {
+			if err := typer.Type(text); err != nil {
+				a.tray.SetError("Type error: " + err.Error())
+				a.events.Error(err)
+				notifier.Error(err.Error())
+				a.ipc.Emit(ipc.ErrorEvent(err.Error()))
+				return
+			}
+			if typing.UsesClipboardPaste(text) {
+				a.ipc.Emit(ipc.ClipboardEvent(text))
+			}
+		}
 	}
+	a.events.Typed(len([]rune(text)))
 
 	a.tray.SetDone(text)
+	notifier.Stop(text)
+	a.ipc.Emit(ipc.FinalEvent(text, time.Since(start).Milliseconds()))
+}
+
+// streamingStabilityThreshold is how confident (Partial.Stability, 0–1) the
+// recognizer must be that an interim transcript won't change again before
+// runStreamingDictation renders it. Rendering every partial regardless of
+// stability would have TypeIncremental backspace-and-retype the same words
+// several times per phrase; IsFinal partials always render, confidence or
+// not, so the committed text never depends on this being tuned right.
+const streamingStabilityThreshold = 0.8
+
+// runStreamingDictation consumes partial transcripts from RecognizeStream,
+// sending every one to the popup and IPC subscribers, and typing each final
+// one, and each interim one at or above streamingStabilityThreshold, over the
+// last typed text via TypeIncremental, so the target app's text converges
+// live instead of appearing all at once at the end. It returns the last
+// transcript it typed once the stream closes, and ctx's error if the stream
+// closed because ctx was canceled or timed out.
+func (a *app) runStreamingDictation(ctx context.Context, rec *speech.Recognizer, typer typing.Typer, audioCh <-chan []byte) (string, error) {
+	partials, err := rec.RecognizeStream(ctx, audioCh)
+	if err != nil {
+		return "", err
+	}
+
+	var last string
+	for p := range partials {
+		a.ipc.Emit(ipc.PartialEvent(p.Text))
+		a.tray.SetPartial(p.Text)
+
+		if !p.IsFinal && p.Stability < streamingStabilityThreshold {
+			continue
+		}
+		if err := typer.TypeIncremental(last, p.Text); err != nil {
+			return last, err
+		}
+		last = p.Text
+	}
+	return last, ctx.Err()
 }
 
 func (a *app) stopDictation() {